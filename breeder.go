@@ -8,10 +8,6 @@ go-galib beeders
 
 package ga
 
-import (
-	"math/rand"
-)
-
 type GABreeder interface {
 	// Breeds two parent GAGenomes and returns two children
 	Breed(a, b GAGenome) (ca, cb GAGenome)
@@ -27,8 +23,8 @@ func (breeder *GA2PointBreeder) Breed(a, b GAGenome) (ca, cb GAGenome) {
 	if a.Len() != b.Len() {
 		panic("Length mismatch in pmx")
 	}
-	p1 := rand.Intn(a.Len())
-	p2 := rand.Intn(b.Len())
+	p1 := rng.Intn(a.Len())
+	p2 := rng.Intn(b.Len())
 	if p1 > p2 {
 		p1, p2 = p2, p1
 	}
@@ -47,7 +43,7 @@ func (u *GAUniformBreeder) Breed(a, b GAGenome) (ca, cb GAGenome) {
 	ca, cb = a.Copy(), b.Copy()
 	length := a.Len()
 	for i := 0; i < length; i++ {
-		if rand.Intn(2) == 0 {
+		if rng.Intn(2) == 0 {
 			ca.Splice(a, i, i, 1)
 			cb.Splice(b, i, i, 1)
 		} else {
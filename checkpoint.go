@@ -0,0 +1,192 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Checkpoints of GA and GAParallel runs. A checkpoint holds the population,
+generation counter, statistics, hall of fame, the state of the random
+number generator and of operators implementing encoding.BinaryMarshaler.
+Operators holding other operators save the state of those only if they pass
+it on, as GAMultiMutator does, else a resumed run does not continue exactly
+where it stopped. The parameters of the run are not saved, Load expects a
+GA set up with the same parameters as the one that was saved. Score
+functions must be registered with RegisterScore.
+*/
+
+package ga
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+type gaCheckpoint struct {
+	Popsize    int
+	Generation int
+	Pop        []gaGenomeRecord
+	HallOfFame []gaGenomeRecord
+	History    []GAStats
+	Operators  [][]byte
+	Rand       []byte
+}
+
+// operators returns the operators of the GA that may carry state.
+func (ga *GA) operators() []interface{} {
+	ops := []interface{}{
+		ga.Parameter.Initializer,
+		ga.Parameter.Selector,
+		ga.Parameter.Mutator,
+		ga.Parameter.Breeder,
+		ga.Parameter.Neural,
+		ga.Parameter.Replacement,
+		ga.Parameter.Evaluator,
+	}
+	for _, s := range ga.schedules {
+		ops = append(ops, s.schedule)
+	}
+	return ops
+}
+
+// Save writes a checkpoint of the GA to w.
+func (ga *GA) Save(w io.Writer) error {
+	var err error
+	cp := gaCheckpoint{Popsize: ga.popsize, Generation: ga.generation, History: ga.history}
+	if cp.Pop, err = encodeGenomes(ga.pop, true); err != nil {
+		return err
+	}
+	if ga.hof != nil {
+		if cp.HallOfFame, err = encodeGenomes(ga.hof.Genomes(), true); err != nil {
+			return err
+		}
+	}
+	for _, op := range ga.operators() {
+		var b []byte
+		if m, ok := op.(encoding.BinaryMarshaler); ok {
+			if b, err = m.MarshalBinary(); err != nil {
+				return err
+			}
+		}
+		cp.Operators = append(cp.Operators, b)
+	}
+	if cp.Rand, err = rngSource.MarshalBinary(); err != nil {
+		return err
+	}
+	return gob.NewEncoder(w).Encode(&cp)
+}
+
+// Load replaces the state of the GA with the checkpoint read from r. The GA
+// needs the same parameters and schedules as the GA that was saved. If the
+// checkpoint can not be read the GA is left as it was.
+func (ga *GA) Load(r io.Reader) error {
+	l, err := ga.decode(r)
+	if err != nil {
+		return err
+	}
+	return ga.load(l)
+}
+
+// A checkpoint read for a GA, not applied yet.
+type gaLoaded struct {
+	cp       gaCheckpoint
+	pop, hof GAGenomes
+}
+
+// decode reads a checkpoint for the GA and checks that it fits the GA.
+// Operator states are tried on clones of the operators implementing
+// GACloner.
+func (ga *GA) decode(r io.Reader) (*gaLoaded, error) {
+	l := new(gaLoaded)
+	if err := gob.NewDecoder(r).Decode(&l.cp); err != nil {
+		return nil, err
+	}
+	ops := ga.operators()
+	if len(ops) != len(l.cp.Operators) {
+		return nil, fmt.Errorf("ga: checkpoint has %d operators, GA has %d", len(l.cp.Operators), len(ops))
+	}
+	var err error
+	if l.pop, err = decodeGenomes(l.cp.Pop); err != nil {
+		return nil, err
+	}
+	if l.hof, err = decodeGenomes(l.cp.HallOfFame); err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if c, ok := op.(GACloner); ok {
+			op = c.Clone()
+		}
+		if u, ok := op.(encoding.BinaryUnmarshaler); ok && l.cp.Operators[i] != nil {
+			if err := u.UnmarshalBinary(l.cp.Operators[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return l, nil
+}
+
+// load replaces the state of the GA with the decoded checkpoint.
+func (ga *GA) load(l *gaLoaded) error {
+	for i, op := range ga.operators() {
+		if u, ok := op.(encoding.BinaryUnmarshaler); ok && l.cp.Operators[i] != nil {
+			if err := u.UnmarshalBinary(l.cp.Operators[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rngSource.UnmarshalBinary(l.cp.Rand); err != nil {
+		return err
+	}
+	ga.pop = l.pop
+	ga.popsize = l.cp.Popsize
+	ga.generation = l.cp.Generation
+	ga.history = l.cp.History
+	ga.err = nil
+	ga.hof = nil
+	if ga.Parameter.HallOfFame > 0 {
+		ga.hof = NewGAHallOfFame(ga.Parameter.HallOfFame)
+		ga.hof.Update(l.hof)
+	}
+	return nil
+}
+
+// Save writes a checkpoint of every island to w.
+func (ga *GAParallel) Save(w io.Writer) error {
+	islands := make([][]byte, ga.numproc)
+	for i := 0; i < ga.numproc; i++ {
+		var b bytes.Buffer
+		if err := ga.ga[i].Save(&b); err != nil {
+			return err
+		}
+		islands[i] = b.Bytes()
+	}
+	return gob.NewEncoder(w).Encode(islands)
+}
+
+// Load replaces the state of every island with the checkpoint read from r.
+// The GAParallel needs the same parameters and number of islands as the one
+// that was saved.
+func (ga *GAParallel) Load(r io.Reader) error {
+	var islands [][]byte
+	if err := gob.NewDecoder(r).Decode(&islands); err != nil {
+		return err
+	}
+	if len(islands) != ga.numproc {
+		return fmt.Errorf("ga: checkpoint has %d islands, GAParallel has %d", len(islands), ga.numproc)
+	}
+	// Every island is read before any is changed, so a bad checkpoint leaves
+	// all of them as they were.
+	loaded := make([]*gaLoaded, ga.numproc)
+	for i := range loaded {
+		var err error
+		if loaded[i], err = ga.ga[i].decode(bytes.NewReader(islands[i])); err != nil {
+			return fmt.Errorf("ga: island %d: %v", i, err)
+		}
+	}
+	for i, l := range loaded {
+		if err := ga.ga[i].load(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
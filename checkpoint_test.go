@@ -0,0 +1,179 @@
+package ga
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	RegisterScore("sphere", sphere)
+}
+
+func checkpointGA() (*GA, *GAMultiMutator) {
+	mm := NewMultiMutator()
+	mm.Add(NewGAGaussianMutator(1, 0))
+	mm.AddWeighted(NewGASelfAdaptiveMutator(1, true), 2)
+	mm.Add(&GASelfAdaptiveMutator{Sigma0: 1, OneFifth: true})
+	ga := NewGA(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     mm,
+		PMutate:     0.5,
+		PBreed:      0.5,
+		HallOfFame:  3})
+	ga.Schedule("PMutate", &ga.Parameter.PMutate, NewGAStagnationSchedule(0.5, 1.1, 0.9, 2))
+	return ga, mm
+}
+
+func populationOf(ga *GA) []string {
+	var s []string
+	for _, g := range ga.pop {
+		s = append(s, g.String())
+	}
+	return s
+}
+
+// Tests that a run resumed from a checkpoint continues exactly like the
+// run that was saved.
+func TestGACheckpoint(t *testing.T) {
+	Seed(1)
+	ga, mm := checkpointGA()
+	ga.Init(20, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	ga.Optimize(5)
+	var b bytes.Buffer
+	if err := ga.Save(&b); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	ga.Optimize(5)
+
+	resumed, rmm := checkpointGA()
+	if err := resumed.Load(&b); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if resumed.Generation() != 5 || len(resumed.History()) != 6 {
+		t.Errorf("Load() generation, history = %d, %d; want 5, 6", resumed.Generation(), len(resumed.History()))
+	}
+	resumed.Optimize(5)
+
+	if got, want := populationOf(resumed), populationOf(ga); !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed population = %v; want %v", got, want)
+	}
+	if got, want := resumed.Stats(), ga.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("resumed Stats() = %v; want %v", got, want)
+	}
+	if got, want := rmm.Stats(), mm.Stats(); got != want {
+		t.Errorf("resumed GAMultiMutator.Stats() = %v; want %v", got, want)
+	}
+	sa, rsa := mm.v[2].(*GASelfAdaptiveMutator), rmm.v[2].(*GASelfAdaptiveMutator)
+	if got, want := rsa.success.scale, sa.success.scale; got != want {
+		t.Errorf("resumed one-fifth step size factor = %v; want %v", got, want)
+	}
+	if got, want := resumed.HallOfFame()[0].String(), ga.HallOfFame()[0].String(); got != want {
+		t.Errorf("resumed HallOfFame()[0] = %v; want %v", got, want)
+	}
+}
+
+func TestGACheckpointUnregistered(t *testing.T) {
+	ga, _ := checkpointGA()
+	ga.Init(5, NewFloatGenome(make([]float64, 2), func(g *GAFloatGenome) float64 { return 0 }, 1, 0))
+	if err := ga.Save(new(bytes.Buffer)); err == nil {
+		t.Errorf("Save() with an unregistered score function = nil; want error")
+	}
+}
+
+func TestGAParallelCheckpoint(t *testing.T) {
+	param := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		PMutate:     0.5,
+		PBreed:      0.5}
+	gap := NewGAParallel(param, 3)
+	gap.Init(10, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	gap.Optimize(3)
+	var b bytes.Buffer
+	if err := gap.Save(&b); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	resumed := NewGAParallel(param, 3)
+	if err := resumed.Load(&b); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	for i := range gap.ga {
+		if got, want := populationOf(resumed.ga[i]), populationOf(gap.ga[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("island %d population = %v; want %v", i, got, want)
+		}
+	}
+	if got, want := resumed.Best().Score(), gap.Best().Score(); got != want {
+		t.Errorf("resumed Best().Score() = %v; want %v", got, want)
+	}
+}
+
+// Tests that a failed Load leaves every island as it was and that a
+// successful one clears the error of the run before.
+func TestCheckpointLoadErrors(t *testing.T) {
+	param := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		PMutate:     0.5,
+		PBreed:      0.5}
+	gap := NewGAParallel(param, 2)
+	gap.Init(10, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	var b bytes.Buffer
+	if err := gap.ga[0].Save(&b); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	var bad bytes.Buffer
+	gob.NewEncoder(&bad).Encode([][]byte{b.Bytes(), []byte("no checkpoint")})
+	other := NewGAParallel(param, 2)
+	other.Init(10, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	before := [][]string{populationOf(other.ga[0]), populationOf(other.ga[1])}
+	if err := other.Load(&bad); err == nil {
+		t.Fatalf("Load() of a broken island = nil; want error")
+	}
+	for i, want := range before {
+		if got := populationOf(other.ga[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("island %d changed by a failed Load()", i)
+		}
+	}
+
+	ga := NewGA(param)
+	ga.err = errors.New("evaluator failed")
+	if err := ga.Load(bytes.NewReader(b.Bytes())); err != nil || ga.Err() != nil {
+		t.Errorf("Load() = %v, Err() = %v; want nil, nil", err, ga.Err())
+	}
+}
+
+// Tests that a GAMultiMutator saves the state of the mutators it holds.
+func TestMultiMutatorCheckpoint(t *testing.T) {
+	sa := &GASelfAdaptiveMutator{Sigma0: 1, OneFifth: true}
+	mm := NewMultiMutator()
+	mm.Add(sa)
+	g := NewFloatGenome([]float64{5, -5}, nil, 10, -10)
+	g.SetScore(sphere(g))
+	for i := 0; i < 20; i++ {
+		c := mm.Mutate(g).(*GAFloatGenome)
+		c.SetScore(sphere(c))
+	}
+	mm.Mutate(g)
+	data, err := mm.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+	rsa := &GASelfAdaptiveMutator{Sigma0: 1, OneFifth: true}
+	rmm := NewMultiMutator()
+	rmm.Add(rsa)
+	if err := rmm.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() = %v", err)
+	}
+	if rsa.success == nil || rsa.success.scale != sa.success.scale {
+		t.Errorf("restored step size factor of the held mutator differs from %v", sa.success.scale)
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Distributed island model. Islands in different processes, or on different
+machines, migrate through a GACoordinator over TCP. Every request and
+response is a gob value on the connection. The coordinator routes emigrants
+along its topology and keeps the best genome any island reported. An island
+whose connection closes, or stays silent for longer than the Idle time of
+the coordinator, has left: it gets no more emigrants and another island can
+join in its place. Genomes travel as the records of genome_encoding.go, so
+every process must register the score functions with RegisterScore under
+the same names.
+*/
+
+package ga
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// Time a coordinator takes to answer, or an island to get an answer, when
+// no timeout is set.
+const gaNetTimeout = time.Minute
+
+type gaRequest struct {
+	// "join" or "migrate"
+	Op        string
+	Island    int
+	Emigrants []gaGenomeRecord
+	Best      *gaGenomeRecord
+}
+
+type gaMigrant struct {
+	From   int
+	Genome gaGenomeRecord
+}
+
+type gaResponse struct {
+	Island     int
+	Islands    int
+	Immigrants []gaMigrant
+	Score      float64
+	Err        string
+}
+
+// Routes migrants between a fixed number of remote islands and tracks the
+// global best genome.
+type GACoordinator struct {
+	// Islands each island sends emigrants to, islands that have left get
+	// none
+	Topology GATopology
+	// Longest wait for the next request of an island before it is dropped,
+	// 0 waits as long as its connection is open
+	Idle time.Duration
+	// Longest time to send an answer to an island, 0 uses one minute
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	islands int
+	state   []gaIslandState
+	mail    [][]gaMigrant
+	best    *gaGenomeRecord
+}
+
+type gaIslandState int
+
+const (
+	// Not joined yet, emigrants for the island wait for it.
+	gaWaiting gaIslandState = iota
+	gaJoined
+	// Joined and left again, no emigrants are sent to the island.
+	gaLeft
+)
+
+// NewGACoordinator returns a coordinator for the given number of islands.
+func NewGACoordinator(islands int, topology GATopology) *GACoordinator {
+	return &GACoordinator{
+		Topology: topology,
+		islands:  islands,
+		state:    make([]gaIslandState, islands),
+		mail:     make([][]gaMigrant, islands),
+	}
+}
+
+// Serve accepts island connections on l until l is closed.
+func (c *GACoordinator) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go c.serve(conn)
+	}
+}
+
+func (c *GACoordinator) serve(conn net.Conn) {
+	island := -1
+	defer func() {
+		conn.Close()
+		if island >= 0 {
+			c.leave(island)
+		}
+	}()
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = gaNetTimeout
+	}
+	enc, dec := gob.NewEncoder(conn), gob.NewDecoder(conn)
+	for {
+		var deadline time.Time
+		if c.Idle > 0 {
+			deadline = time.Now().Add(c.Idle)
+		}
+		conn.SetReadDeadline(deadline)
+		var req gaRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		resp := c.handle(&req, island)
+		if req.Op == "join" && resp.Err == "" {
+			island = resp.Island
+		}
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle answers req from the connection of island, -1 if it has not
+// joined.
+func (c *GACoordinator) handle(req *gaRequest, island int) (resp gaResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp.Islands = c.islands
+	switch req.Op {
+	case "join":
+		if island >= 0 {
+			resp.Err = fmt.Sprintf("ga: already joined as island %d", island)
+			return
+		}
+		resp.Island = c.free()
+		if resp.Island < 0 {
+			resp.Err = fmt.Sprintf("ga: all %d islands have joined", c.islands)
+			return
+		}
+		c.state[resp.Island] = gaJoined
+	case "migrate":
+		i := req.Island
+		if i < 0 || i != island {
+			resp.Err = fmt.Sprintf("ga: island %d has not joined on this connection", i)
+			return
+		}
+		if b := req.Best; b != nil && b.HasScore && (c.best == nil || b.Cached < c.best.Cached) {
+			c.best = b
+		}
+		if len(req.Emigrants) > 0 {
+			for _, j := range c.targets(i) {
+				for _, g := range req.Emigrants {
+					c.mail[j] = append(c.mail[j], gaMigrant{i, g})
+				}
+			}
+		}
+		resp.Island = i
+		resp.Immigrants, c.mail[i] = c.mail[i], nil
+	default:
+		resp.Err = fmt.Sprintf("ga: unknown request %q", req.Op)
+		return
+	}
+	resp.Score = c.score()
+	return
+}
+
+// free returns the first island that has not joined yet, else the first
+// that has left, -1 if all have joined.
+func (c *GACoordinator) free() int {
+	left := -1
+	for i, s := range c.state {
+		switch {
+		case s == gaWaiting:
+			return i
+		case s == gaLeft && left < 0:
+			left = i
+		}
+	}
+	return left
+}
+
+// targets returns the islands of the topology island i sends emigrants to
+// that have not left.
+func (c *GACoordinator) targets(i int) []int {
+	if c.Topology == nil {
+		return nil
+	}
+	var targets []int
+	for _, j := range c.Topology.Targets(i, c.islands) {
+		if c.state[j] != gaLeft {
+			targets = append(targets, j)
+		}
+	}
+	return targets
+}
+
+// leave takes island i out of the topology and drops its mail.
+func (c *GACoordinator) leave(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state[i] = gaLeft
+	c.mail[i] = nil
+}
+
+func (c *GACoordinator) score() float64 {
+	if c.best == nil {
+		return math.Inf(1)
+	}
+	return c.best.Cached
+}
+
+// Joined returns the number of islands that have joined and not left.
+func (c *GACoordinator) Joined() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, s := range c.state {
+		if s == gaJoined {
+			n++
+		}
+	}
+	return n
+}
+
+// Best returns the best genome reported by any island, nil if none has
+// been reported yet.
+func (c *GACoordinator) Best() (GAGenome, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.best == nil {
+		return nil, nil
+	}
+	return decodeGenome(*c.best)
+}
+
+// A GA taking part in a distributed island model through a GACoordinator.
+type GARemoteIsland struct {
+	GA *GA
+	// Interval, Size, Emigrants, Immigrants and Convert of the migrations,
+	// the Topology of the coordinator picks the islands emigrants go to
+	Migration GAMigration
+	// Longest time a request to the coordinator may take, 0 uses one minute
+	Timeout time.Duration
+
+	island  int
+	islands int
+	score   float64
+	conn    net.Conn
+	enc     *gob.Encoder
+	dec     *gob.Decoder
+}
+
+// DialGARemoteIsland connects ga to the coordinator at addr and joins it as
+// the first island that has not joined or has left. The GA must be
+// initialized before Optimize is called.
+func DialGARemoteIsland(addr string, ga *GA) (*GARemoteIsland, error) {
+	return DialGARemoteIslandTimeout(addr, ga, 0)
+}
+
+// DialGARemoteIslandTimeout is DialGARemoteIsland with a Timeout for the
+// connection and every request, 0 uses one minute.
+func DialGARemoteIslandTimeout(addr string, ga *GA, timeout time.Duration) (*GARemoteIsland, error) {
+	r := &GARemoteIsland{
+		GA:        ga,
+		Migration: GAMigration{Interval: 1, Size: 2},
+		Timeout:   timeout,
+		score:     math.Inf(1),
+	}
+	conn, err := net.DialTimeout("tcp", addr, r.timeout())
+	if err != nil {
+		return nil, err
+	}
+	r.conn, r.enc, r.dec = conn, gob.NewEncoder(conn), gob.NewDecoder(conn)
+	resp, err := r.call(&gaRequest{Op: "join"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	r.island, r.islands = resp.Island, resp.Islands
+	return r, nil
+}
+
+func (r *GARemoteIsland) timeout() time.Duration {
+	if r.Timeout > 0 {
+		return r.Timeout
+	}
+	return gaNetTimeout
+}
+
+func (r *GARemoteIsland) call(req *gaRequest) (*gaResponse, error) {
+	r.conn.SetDeadline(time.Now().Add(r.timeout()))
+	if err := r.enc.Encode(req); err != nil {
+		return nil, err
+	}
+	resp := new(gaResponse)
+	if err := r.dec.Decode(resp); err != nil {
+		return nil, err
+	}
+	if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return resp, nil
+}
+
+// Island returns the number the coordinator gave this island.
+func (r *GARemoteIsland) Island() int { return r.island }
+
+// Islands returns the number of islands of the coordinator.
+func (r *GARemoteIsland) Islands() int { return r.islands }
+
+// GlobalBest returns the best score any island had reported to the
+// coordinator at the last migration.
+func (r *GARemoteIsland) GlobalBest() float64 { return r.score }
+
+// Optimize runs gen generations, migrating every Migration.Interval
+// generations. Emigrants sent by other islands since the last migration
+// arrive at the next one.
+func (r *GARemoteIsland) Optimize(gen int) error {
+	for gen > 0 {
+		step := gen
+		if m := r.Migration.Interval; m > 0 {
+			if s := m - r.GA.generation%m; s < step {
+				step = s
+			}
+		}
+		r.GA.Optimize(step)
+		if err := r.GA.Err(); err != nil {
+			return err
+		}
+		gen -= step
+		if m := r.Migration.Interval; m > 0 && r.GA.generation%m == 0 {
+			if err := r.migrate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrate sends emigrants and the best genome of the island to the
+// coordinator and takes in the immigrants waiting there.
+func (r *GARemoteIsland) migrate() error {
+	req := &gaRequest{Op: "migrate", Island: r.island}
+	var err error
+	if r.Migration.Size > 0 {
+		if req.Emigrants, err = encodeGenomes(r.Migration.emigrants(r.GA), true); err != nil {
+			return err
+		}
+	}
+	b := r.GA.Best()
+	b.Score() // Makes sure the record holds the score.
+	best, err := encodeGenome(b, true)
+	if err != nil {
+		return err
+	}
+	req.Best = &best
+	resp, err := r.call(req)
+	if err != nil {
+		return err
+	}
+	r.score = resp.Score
+	if len(resp.Immigrants) == 0 {
+		return nil
+	}
+	immigrants := make(GAGenomes, len(resp.Immigrants))
+	for i, m := range resp.Immigrants {
+		g, err := decodeGenome(m.Genome)
+		if err != nil {
+			return err
+		}
+		if r.Migration.Convert != nil {
+			g = r.Migration.Convert(g, m.From, r.island)
+		}
+		immigrants[i] = g
+	}
+	r.Migration.immigrate(r.GA, immigrants)
+	return nil
+}
+
+// Close leaves the coordinator.
+func (r *GARemoteIsland) Close() error { return r.conn.Close() }
@@ -0,0 +1,218 @@
+package ga
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// coordinator serves c on a loopback port and returns its address.
+func coordinator(t *testing.T, c *GACoordinator) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go c.Serve(l)
+	return l.Addr().String()
+}
+
+func TestRemoteIslands(t *testing.T) {
+	c := NewGACoordinator(2, new(GARingTopology))
+	addr := coordinator(t, c)
+	gas := islands(2)
+	var remote []*GARemoteIsland
+	for i, ga := range gas {
+		r, err := DialGARemoteIsland(addr, ga)
+		if err != nil {
+			t.Fatalf("DialGARemoteIsland() = %v", err)
+		}
+		defer r.Close()
+		if r.Island() != i || r.Islands() != 2 {
+			t.Errorf("island %d joined as %d of %d", i, r.Island(), r.Islands())
+		}
+		remote = append(remote, r)
+	}
+	if _, err := DialGARemoteIsland(addr, NewGA(GAParameter{})); err == nil {
+		t.Errorf("third island joined a coordinator for 2")
+	}
+
+	// Island 1 gets what island 0 sent at once, island 0 what island 1 sent
+	// at its next migration.
+	for _, r := range remote {
+		if err := r.migrate(); err != nil {
+			t.Fatalf("migrate() = %v", err)
+		}
+	}
+	if got := firstGenes(gas[1].pop); !reflect.DeepEqual(got, []int{0, 1, 10, 11, 12, 13, 14, 15, 16, 17}) {
+		t.Errorf("island 1 after migration = %v", got)
+	}
+	if got := firstGenes(gas[0].pop); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("island 0 after migration = %v", got)
+	}
+	if err := remote[0].migrate(); err != nil {
+		t.Fatalf("migrate() = %v", err)
+	}
+	if got := firstGenes(gas[0].pop); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 10, 11}) {
+		t.Errorf("island 0 after second migration = %v", got)
+	}
+	if remote[0].GlobalBest() != 0 {
+		t.Errorf("GlobalBest() = %v; want 0", remote[0].GlobalBest())
+	}
+	if best, err := c.Best(); err != nil || best.(*GAIntGenome).Gene[0] != 0 {
+		t.Errorf("coordinator Best() = %v, %v; want genome 0", best, err)
+	}
+}
+
+// waitJoined waits for the coordinator to see n islands joined.
+func waitJoined(t *testing.T, c *GACoordinator, n int) {
+	for start := time.Now(); c.Joined() != n; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("Joined() = %d; want %d", c.Joined(), n)
+		}
+	}
+}
+
+func TestRemoteIslandLeave(t *testing.T) {
+	c := NewGACoordinator(3, new(GAFullTopology))
+	addr := coordinator(t, c)
+	gas := islands(3)
+	var remote []*GARemoteIsland
+	for _, ga := range gas[:2] {
+		r, err := DialGARemoteIsland(addr, ga)
+		if err != nil {
+			t.Fatalf("DialGARemoteIsland() = %v", err)
+		}
+		defer r.Close()
+		remote = append(remote, r)
+	}
+	remote[1].Close()
+	waitJoined(t, c, 1)
+	if err := remote[0].migrate(); err != nil {
+		t.Fatalf("migrate() = %v", err)
+	}
+	// Island 2 has not joined yet and gets the emigrants, island 1 has left.
+	c.mu.Lock()
+	if len(c.mail[1]) != 0 || len(c.mail[2]) != 2 {
+		t.Errorf("mail for islands 1 and 2 = %d, %d; want 0, 2", len(c.mail[1]), len(c.mail[2]))
+	}
+	c.mu.Unlock()
+
+	// New islands take the slot that never joined first, then the one that
+	// was left.
+	for _, want := range []int{2, 1} {
+		r, err := DialGARemoteIsland(addr, islands(1)[0])
+		if err != nil {
+			t.Fatalf("DialGARemoteIsland() = %v", err)
+		}
+		defer r.Close()
+		if r.Island() != want {
+			t.Errorf("new island joined as %d; want %d", r.Island(), want)
+		}
+	}
+	if _, err := DialGARemoteIsland(addr, NewGA(GAParameter{})); err == nil {
+		t.Errorf("fourth island joined a coordinator for 3")
+	}
+}
+
+func TestRemoteIslandTimeout(t *testing.T) {
+	// A silent island is dropped after the Idle time of the coordinator.
+	c := NewGACoordinator(2, new(GARingTopology))
+	c.Idle = 50 * time.Millisecond
+	r, err := DialGARemoteIsland(coordinator(t, c), islands(1)[0])
+	if err != nil {
+		t.Fatalf("DialGARemoteIsland() = %v", err)
+	}
+	defer r.Close()
+	waitJoined(t, c, 0)
+	if err := r.migrate(); err == nil {
+		t.Errorf("migrate() of a dropped island succeeded")
+	}
+
+	// A coordinator that never answers does not block the island for ever.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() = %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	start := time.Now()
+	if _, err := DialGARemoteIslandTimeout(l.Addr().String(), NewGA(GAParameter{}), 50*time.Millisecond); err == nil {
+		t.Errorf("DialGARemoteIslandTimeout() to a silent coordinator succeeded")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("DialGARemoteIslandTimeout() took %v with a timeout of 50ms", d)
+	}
+}
+
+// TestRemoteIslandProcess is run as an island process by
+// TestRemoteIslandProcesses.
+func TestRemoteIslandProcess(t *testing.T) {
+	addr := os.Getenv("GA_COORDINATOR")
+	if addr == "" {
+		t.Skip("run by TestRemoteIslandProcesses")
+	}
+	ga := NewGA(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		PMutate:     0.5})
+	ga.Init(20, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	r, err := DialGARemoteIsland(addr, ga)
+	if err != nil {
+		t.Fatalf("DialGARemoteIsland() = %v", err)
+	}
+	defer r.Close()
+	r.Migration.Interval = 5
+	if err := r.Optimize(50); err != nil {
+		t.Fatalf("Optimize() = %v", err)
+	}
+	if r.GlobalBest() > ga.Best().Score() {
+		t.Errorf("GlobalBest() = %v; want at most the island best %v", r.GlobalBest(), ga.Best().Score())
+	}
+}
+
+func TestRemoteIslandProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts island processes")
+	}
+	if os.Getenv("GA_COORDINATOR") != "" {
+		t.Skip("in an island process")
+	}
+	const n = 3
+	c := NewGACoordinator(n, new(GAFullTopology))
+	addr := coordinator(t, c)
+	cmds := make([]*exec.Cmd, n)
+	for i := range cmds {
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^TestRemoteIslandProcess$")
+		cmds[i].Env = append(os.Environ(), "GA_COORDINATOR="+addr)
+		if err := cmds[i].Start(); err != nil {
+			t.Fatalf("starting island %d: %v", i, err)
+		}
+	}
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("island process %d: %v", i, err)
+		}
+	}
+	// The island processes have exited, so they left.
+	waitJoined(t, c, 0)
+	best, err := c.Best()
+	if err != nil || best == nil {
+		t.Fatalf("Best() = %v, %v; want a genome", best, err)
+	}
+	if s := best.Score(); s > 1 {
+		t.Errorf("Best().Score() = %v after 50 generations on 3 islands; want at most 1", s)
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Population diversity measures. All measures are 0 when every genome in the
+population is the same and grow as the population spreads out.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+// Reseeds part of the population when its diversity drops below Threshold.
+type GARestart struct {
+	// Diversity below which the population is reseeded
+	Threshold float64
+	// Part of the population replaced by new genomes from the initializer,
+	// the best genomes are kept
+	Fraction float64
+	// Initializer of the new genomes, nil uses the one of the GA. Seeding
+	// initializers such as GASeededInitializer and GAImportInitializer put
+	// the same genomes back at every restart, a GARandomInitializer does not
+	Initializer GAInitializer
+}
+
+// Diversity returns the diversity of pop using the measure that fits the
+// genome type: mean pairwise Hamming distance for GAFixedBitstringGenome and
+// GAIntGenome, spread around the centroid for GAFloatGenome and
+// GAFloat32Genome and mean pairwise Kendall tau distance for
+// GAOrderedIntGenome. Other genomes use the mean locus entropy.
+func Diversity(pop GAGenomes) float64 {
+	if len(pop) < 2 {
+		return 0
+	}
+	switch pop[0].(type) {
+	case *GAFixedBitstringGenome, *GAIntGenome:
+		return HammingDiversity(pop)
+	case *GAFloatGenome, *GAFloat32Genome:
+		return EuclideanDiversity(pop)
+	case *GAOrderedIntGenome:
+		return KendallTauDiversity(pop)
+	}
+	return EntropyDiversity(pop)
+}
+
+// HammingDiversity returns the mean pairwise Hamming distance of pop divided
+// by the genome length. A random bitstring population scores about 0.5.
+func HammingDiversity(pop GAGenomes) float64 {
+	n := len(pop)
+	if n < 2 || pop[0].Len() == 0 {
+		return 0
+	}
+	l := pop[0].Len()
+	pairs := float64(n*(n-1)) / 2
+	var differ float64
+	for i := 0; i < l; i++ {
+		// Pairs that differ at locus i are all pairs minus those with
+		// equal genes.
+		same := 0.0
+		for _, c := range locusCounts(pop, i) {
+			same += float64(c*(c-1)) / 2
+		}
+		differ += pairs - same
+	}
+	return differ / pairs / float64(l)
+}
+
+// EuclideanDiversity returns the mean distance of the genomes of pop to
+// their centroid, scaled so a population spread uniformly over the range of
+// the genome scores about 1. For genomes without a range the distance is not
+// scaled.
+func EuclideanDiversity(pop GAGenomes) float64 {
+	n := len(pop)
+	if n < 2 {
+		return 0
+	}
+	vectors := make([][]float64, n)
+	for i, g := range pop {
+		vectors[i] = realGenes(g)
+	}
+	l := len(vectors[0])
+	if l == 0 {
+		return 0
+	}
+	centroid := make([]float64, l)
+	for _, v := range vectors {
+		for j, c := range v {
+			centroid[j] += c / float64(n)
+		}
+	}
+	var sum float64
+	for _, v := range vectors {
+		var d float64
+		for j, c := range v {
+			d += (c - centroid[j]) * (c - centroid[j])
+		}
+		sum += math.Sqrt(d)
+	}
+	spread := sum / float64(n)
+	if min, max := realRange(pop[0]); max > min {
+		// Scale by the spread of a uniform random population, which is
+		// about sqrt(l/12) times the range.
+		spread /= (max - min) * math.Sqrt(float64(l)/12)
+	}
+	return spread
+}
+
+// KendallTauDiversity returns the mean pairwise Kendall tau distance of the
+// permutations in pop divided by the largest possible distance. A random
+// population scores about 0.5.
+func KendallTauDiversity(pop GAGenomes) float64 {
+	n := len(pop)
+	if n < 2 {
+		return 0
+	}
+	l := pop[0].Len()
+	if l < 2 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		a := pop[i].(*GAOrderedIntGenome).Gene
+		for j := i + 1; j < n; j++ {
+			sum += float64(KendallTau(a, pop[j].(*GAOrderedIntGenome).Gene))
+		}
+	}
+	pairs := float64(n*(n-1)) / 2
+	return sum / pairs / (float64(l*(l-1)) / 2)
+}
+
+// KendallTau returns the number of pairs of elements the permutations a and b
+// put in different order.
+func KendallTau(a, b []int) int {
+	pos := make(map[int]int, len(b))
+	for i, c := range b {
+		pos[c] = i
+	}
+	// Count inversions of a expressed as positions in b.
+	s := make([]int, len(a))
+	for i, c := range a {
+		s[i] = pos[c]
+	}
+	return inversions(s, make([]int, len(s)))
+}
+
+// inversions counts the inversions of s by merge sort, sorting s.
+func inversions(s, tmp []int) int {
+	if len(s) < 2 {
+		return 0
+	}
+	m := len(s) / 2
+	n := inversions(s[:m], tmp[:m]) + inversions(s[m:], tmp[m:])
+	i, j, k := 0, m, 0
+	for i < m && j < len(s) {
+		if s[i] <= s[j] {
+			tmp[k] = s[i]
+			i++
+		} else {
+			tmp[k] = s[j]
+			j++
+			n += m - i
+		}
+		k++
+	}
+	k += copy(tmp[k:], s[i:m])
+	copy(tmp[k:], s[j:])
+	copy(s, tmp)
+	return n
+}
+
+// LocusEntropy returns the Shannon entropy of the genes at every locus of pop
+// in bits. Real valued genes are put in 10 bins over the range of the genome.
+func LocusEntropy(pop GAGenomes) []float64 {
+	if len(pop) == 0 {
+		return nil
+	}
+	l := pop[0].Len()
+	e := make([]float64, l)
+	n := float64(len(pop))
+	for i := 0; i < l; i++ {
+		for _, c := range locusCounts(pop, i) {
+			p := float64(c) / n
+			e[i] -= p * math.Log2(p)
+		}
+	}
+	return e
+}
+
+// EntropyDiversity returns the mean locus entropy of pop divided by the
+// largest entropy possible for the population size.
+func EntropyDiversity(pop GAGenomes) float64 {
+	if len(pop) < 2 {
+		return 0
+	}
+	e := LocusEntropy(pop)
+	if len(e) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, c := range e {
+		sum += c
+	}
+	return sum / float64(len(e)) / math.Log2(float64(len(pop)))
+}
+
+// locusCounts returns how often every gene value occurs at locus i of pop.
+func locusCounts(pop GAGenomes, i int) map[interface{}]int {
+	counts := make(map[interface{}]int)
+	for _, g := range pop {
+		counts[geneKey(g, i)]++
+	}
+	return counts
+}
+
+func geneKey(g GAGenome, i int) interface{} {
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		return g.Gene[i]
+	case *GAIntGenome:
+		return g.Gene[i]
+	case *GAOrderedIntGenome:
+		return g.Gene[i]
+	case *GAFloatGenome:
+		return bin(g.Gene[i], g.Min, g.Max)
+	case *GAFloat32Genome:
+		return bin(float64(g.Gene[i]), float64(g.Min), float64(g.Max))
+	}
+	// Fall back to the string of a copy with every gene replaced by gene i.
+	c := g.Copy()
+	for j := 0; j < c.Len(); j++ {
+		if j != i {
+			c.Splice(g, i, j, 1)
+		}
+	}
+	return c.String()
+}
+
+// bin returns which of 10 bins over [min,max] x falls in.
+func bin(x, min, max float64) int {
+	if max <= min {
+		return int(math.Floor(x))
+	}
+	b := int(math.Floor((x - min) / (max - min) * 10))
+	if b < 0 {
+		return 0
+	}
+	if b > 9 {
+		return 9
+	}
+	return b
+}
+
+// realGenes returns the genes of a real valued genome as float64.
+func realGenes(g GAGenome) []float64 {
+	switch g := g.(type) {
+	case *GAFloatGenome:
+		return g.Gene
+	case *GAFloat32Genome:
+		v := make([]float64, len(g.Gene))
+		for i, c := range g.Gene {
+			v[i] = float64(c)
+		}
+		return v
+	}
+	panic("Real valued genome needs to be GAFloatGenome or GAFloat32Genome")
+}
+
+// realRange returns the Min and Max of a real valued genome.
+func realRange(g GAGenome) (min, max float64) {
+	switch g := g.(type) {
+	case *GAFloatGenome:
+		return math.Min(g.Min, g.Max), math.Max(g.Min, g.Max)
+	case *GAFloat32Genome:
+		return math.Min(float64(g.Min), float64(g.Max)), math.Max(float64(g.Min), float64(g.Max))
+	}
+	return 0, 0
+}
+
+// Distance between two genomes, used by the crowding replacements.
+type GADistance func(a, b GAGenome) float64
+
+// Distance returns the distance between two genomes of the same type: the
+// Hamming distance for GAFixedBitstringGenome and GAIntGenome, the Euclidean
+// distance for GAFloatGenome and GAFloat32Genome and the Kendall tau distance
+// for GAOrderedIntGenome. Other genomes count the genes that differ.
+func Distance(a, b GAGenome) float64 {
+	switch a := a.(type) {
+	case *GAFixedBitstringGenome:
+		d := 0
+		for i, c := range b.(*GAFixedBitstringGenome).Gene {
+			if c != a.Gene[i] {
+				d++
+			}
+		}
+		return float64(d)
+	case *GAIntGenome:
+		d := 0
+		for i, c := range b.(*GAIntGenome).Gene {
+			if c != a.Gene[i] {
+				d++
+			}
+		}
+		return float64(d)
+	case *GAFloatGenome, *GAFloat32Genome:
+		va, vb := realGenes(a), realGenes(b)
+		var d float64
+		for i, c := range va {
+			d += (c - vb[i]) * (c - vb[i])
+		}
+		return math.Sqrt(d)
+	case *GAOrderedIntGenome:
+		return float64(KendallTau(a.Gene, b.(*GAOrderedIntGenome).Gene))
+	}
+	d := 0
+	for i := 0; i < a.Len(); i++ {
+		if geneKey(a, i) != geneKey(b, i) {
+			d++
+		}
+	}
+	return float64(d)
+}
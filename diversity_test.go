@@ -0,0 +1,147 @@
+package ga
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKendallTau(t *testing.T) {
+	tests := []struct {
+		a, b []int
+		want int
+	}{
+		{[]int{0, 1, 2, 3}, []int{0, 1, 2, 3}, 0},
+		{[]int{0, 1, 2, 3}, []int{1, 0, 2, 3}, 1},
+		{[]int{0, 1, 2, 3}, []int{3, 2, 1, 0}, 6},
+		{[]int{2, 0, 3, 1}, []int{0, 1, 2, 3}, 3},
+	}
+	for _, test := range tests {
+		if got := KendallTau(test.a, test.b); got != test.want {
+			t.Errorf("KendallTau(%v, %v) = %d; want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestDiversity(t *testing.T) {
+	bits := func(b ...bool) GAGenome { return NewFixedBitstringGenome(b, nil) }
+	perm := func(p ...int) GAGenome { return NewOrderedIntGenome(p, nil) }
+	float := func(f ...float64) GAGenome { return NewFloatGenome(f, nil, 1, 0) }
+	tests := []struct {
+		name string
+		pop  GAGenomes
+		want float64
+	}{
+		{"same bits", GAGenomes{bits(true, false), bits(true, false), bits(true, false)}, 0},
+		{"half bits", GAGenomes{bits(true, false), bits(true, true)}, 0.5},
+		{"all bits", GAGenomes{bits(true, false), bits(false, true)}, 1},
+		{"same perm", GAGenomes{perm(0, 1, 2), perm(0, 1, 2)}, 0},
+		{"reversed perm", GAGenomes{perm(0, 1, 2), perm(2, 1, 0)}, 1},
+		{"same float", GAGenomes{float(0.5, 0.5), float(0.5, 0.5)}, 0},
+	}
+	for _, test := range tests {
+		if got := Diversity(test.pop); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("Diversity(%s) = %v; want %v", test.name, got, test.want)
+		}
+	}
+
+	pop := make(GAGenomes, 1000)
+	for i := range pop {
+		pop[i] = NewFloatGenome(make([]float64, 10), nil, 1, 0)
+		pop[i].Randomize()
+	}
+	if got := Diversity(pop); math.Abs(got-1) > 0.1 {
+		t.Errorf("Diversity(random floats) = %v; want about 1", got)
+	}
+	e := LocusEntropy(GAGenomes{bits(true, false), bits(false, false)})
+	if e[0] != 1 || e[1] != 0 {
+		t.Errorf("LocusEntropy = %v; want [1 0]", e)
+	}
+}
+
+// Tests that GA reseeds the population once it has converged.
+func TestGARestart(t *testing.T) {
+	ga := NewGA(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     GANoopMutator{},
+		PMutate:     0.1,
+		PBreed:      0.5,
+		Elite:       1,
+		Restart:     &GARestart{Threshold: 0.3, Fraction: 0.5}})
+	ga.Init(20, NewFixedBitstringGenome(make([]bool, 20), func(g *GAFixedBitstringGenome) float64 {
+		return float64(20 - countTrue(g.Gene))
+	}))
+	restarts := 0
+	for i := 0; i < 100; i++ {
+		ga.Optimize(1)
+		s := ga.Stats()
+		if s.Restarted > 0 {
+			restarts++
+			if s.Diversity >= 0.3 || s.Restarted != 10 {
+				t.Errorf("restarted %d genomes at diversity %v", s.Restarted, s.Diversity)
+			}
+		}
+	}
+	if restarts == 0 {
+		t.Errorf("population was never reseeded, diversity %v", ga.Stats().Diversity)
+	}
+}
+
+// Tests that a restart keeps the best genome even when it replaces the whole
+// population with an initializer that randomizes its template.
+func TestGARestartKeepsBest(t *testing.T) {
+	ga := NewGA(GAParameter{
+		Initializer: new(GAHRandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     GANoopMutator{},
+		PMutate:     0.1,
+		PBreed:      0.5,
+		Restart:     &GARestart{Threshold: 1, Fraction: 1}})
+	ga.Init(20, NewFixedBitstringGenome(make([]bool, 20), func(g *GAFixedBitstringGenome) float64 {
+		return float64(20 - countTrue(g.Gene))
+	}))
+	for i := 0; i < 50; i++ {
+		best := ga.Best()
+		score, genes := best.Score(), best.Copy().(*GAFixedBitstringGenome).Gene
+		ga.Optimize(1)
+		if s := ga.Stats(); s.Restarted == 0 || s.Restarted == 20 {
+			t.Fatalf("restarted %d of 20 genomes; want all but the best", s.Restarted)
+		}
+		if ga.Best().Score() > score {
+			t.Fatalf("best score went from %v to %v", score, ga.Best().Score())
+		}
+		if !reflect.DeepEqual(best.(*GAFixedBitstringGenome).Gene, genes) {
+			t.Fatalf("the best genome was changed by the restart")
+		}
+	}
+}
+
+// Tests that a restart with an Initializer of its own does not put the seeds
+// of the GA back.
+func TestGARestartInitializer(t *testing.T) {
+	score := func(g *GAFixedBitstringGenome) float64 { return float64(20 - countTrue(g.Gene)) }
+	seed := NewFixedBitstringGenome(make([]bool, 20), score)
+	ga := NewGA(GAParameter{
+		Initializer: NewGASeededInitializer(seed, seed, seed, seed, seed),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Mutator:     GANoopMutator{},
+		Restart:     &GARestart{Threshold: 1, Fraction: 0.5, Initializer: new(GARandomInitializer)}})
+	ga.Init(10, seed)
+	ga.Optimize(1)
+	if s := ga.Stats(); s.Restarted != 5 {
+		t.Fatalf("restarted %d genomes; want 5", s.Restarted)
+	}
+	seeds := 0
+	for _, g := range ga.pop {
+		if countTrue(g.(*GAFixedBitstringGenome).Gene) == 0 {
+			seeds++
+		}
+	}
+	// The seeds were the worst genomes, so the restart replaced them all.
+	if seeds != 0 {
+		t.Errorf("%d copies of the seed after a restart with a random initializer; want 0", seeds)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+go-galib evaluators, which compute the scores of many genomes at once,
+for example on remote workers, instead of each genome computing its own
+score when it is first asked for.
+*/
+
+package ga
+
+type GAEvaluator interface {
+	// Evaluate caches the score of every genome of pop that implements
+	// GAScoreSetter and has no score yet.
+	Evaluate(pop GAGenomes) error
+	// String name of evaluator
+	String() string
+}
+
+// unscored returns the distinct genomes of pop that implement GAScoreSetter
+// and have no cached score.
+func unscored(pop GAGenomes) GAGenomes {
+	var out GAGenomes
+	seen := make(map[GAGenome]bool)
+	for _, g := range pop {
+		if s, ok := g.(GAScoreSetter); ok && !s.HasScore() && !seen[g] {
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// scorable reports whether every genome of pop can compute its own score.
+// Built-in genomes need a score function for that, others are assumed to.
+func scorable(pop GAGenomes) bool {
+	for _, g := range pop {
+		var ok bool
+		switch g := g.(type) {
+		case *GAFixedBitstringGenome:
+			ok = g.sfunc != nil
+		case *GAIntGenome:
+			ok = g.sfunc != nil
+		case *GAOrderedIntGenome:
+			ok = g.sfunc != nil
+		case *GAFloatGenome:
+			ok = g.sfunc != nil
+		case *GAFloat32Genome:
+			ok = g.sfunc != nil
+		default:
+			ok = true
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Master-worker evaluation over HTTP. The GAHTTPEvaluator posts the genes of
+batches of genomes as JSON to a pool of workers and caches the scores they
+answer with. A request body is {"genes": [[...], ...]} and the response
+{"scores": [...]}, one score per genome in the same order, so workers need
+not be written in Go. GAWorker serves such requests.
+*/
+
+package ga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type gaEvaluateRequest struct {
+	Genes []json.RawMessage `json:"genes"`
+}
+
+type gaEvaluateResponse struct {
+	Scores []float64 `json:"scores"`
+}
+
+type GAHTTPEvaluator struct {
+	// URLs the workers serve requests on
+	Workers []string
+	// Genomes sent in one request, 0 spreads them evenly over the workers
+	Batch int
+	// Time allowed for one request, 0 waits as long as the Client does
+	Timeout time.Duration
+	// Number of times a failed request is sent again, to the next idle
+	// worker
+	Retries int
+	// Score the genomes of a request that failed every retry locally
+	// instead of returning an error, if they all have score functions
+	Local bool
+	// Client used for the requests, nil uses http.DefaultClient
+	Client *http.Client
+}
+
+func NewGAHTTPEvaluator(workers ...string) *GAHTTPEvaluator {
+	return &GAHTTPEvaluator{Workers: workers}
+}
+
+// Evaluate sends the genomes without a score to the workers in batches.
+// Every worker handles one batch at a time. A worker that fails a request is
+// not used again during this call.
+func (e *GAHTTPEvaluator) Evaluate(pop GAGenomes) error {
+	todo := unscored(pop)
+	if len(todo) == 0 {
+		return nil
+	}
+	if len(e.Workers) == 0 {
+		return fmt.Errorf("ga: %s has no workers", e)
+	}
+	batch := e.Batch
+	if batch <= 0 {
+		batch = (len(todo) + len(e.Workers) - 1) / len(e.Workers)
+	}
+	pool := &gaWorkerPool{idle: append([]string(nil), e.Workers...)}
+	pool.cond = sync.NewCond(&pool.mu)
+	var wg sync.WaitGroup
+	errs := make(chan error, (len(todo)+batch-1)/batch)
+	for i := 0; i < len(todo); i += batch {
+		j := i + batch
+		if j > len(todo) {
+			j = len(todo)
+		}
+		wg.Add(1)
+		go func(b GAGenomes) {
+			defer wg.Done()
+			errs <- e.batch(b, pool)
+		}(todo[i:j])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch evaluates the genomes of b on the workers of the pool, retrying
+// failed requests.
+func (e *GAHTTPEvaluator) batch(b GAGenomes, pool *gaWorkerPool) error {
+	req := gaEvaluateRequest{Genes: make([]json.RawMessage, len(b))}
+	for i, g := range b {
+		var err error
+		if req.Genes[i], err = geneJSON(g); err != nil {
+			return err
+		}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	for try := 0; try <= e.Retries; try++ {
+		w, ok := pool.take()
+		if !ok {
+			err = fmt.Errorf("ga: all workers of %s failed", e)
+			break
+		}
+		var scores []float64
+		scores, err = e.post(w, body, len(b))
+		pool.put(w, err != nil)
+		if err == nil {
+			for i, g := range b {
+				g.(GAScoreSetter).SetScore(scores[i])
+			}
+			return nil
+		}
+	}
+	if e.Local && scorable(b) {
+		for _, g := range b {
+			g.Score()
+		}
+		return nil
+	}
+	return err
+}
+
+// post sends one request to the worker at url and returns the n scores it
+// answers with.
+func (e *GAHTTPEvaluator) post(url string, body []byte, n int) ([]float64, error) {
+	ctx := context.Background()
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ga: worker %s: %s", url, resp.Status)
+	}
+	var r gaEvaluateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("ga: worker %s: %v", url, err)
+	}
+	if len(r.Scores) != n {
+		return nil, fmt.Errorf("ga: worker %s sent %d scores for %d genomes", url, len(r.Scores), n)
+	}
+	return r.Scores, nil
+}
+
+func (e *GAHTTPEvaluator) String() string { return "GAHTTPEvaluator" }
+
+// The workers of one Evaluate call.
+type gaWorkerPool struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	idle []string
+	busy int
+}
+
+// take waits for an idle worker, it fails when no worker is left.
+func (p *gaWorkerPool) take() (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.idle) == 0 {
+		if p.busy == 0 {
+			return "", false
+		}
+		p.cond.Wait()
+	}
+	w := p.idle[0]
+	p.idle = p.idle[1:]
+	p.busy++
+	return w, true
+}
+
+// put gives back a worker taken from the pool, which drops it if it failed.
+func (p *gaWorkerPool) put(w string, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.busy--
+	if !failed {
+		p.idle = append(p.idle, w)
+	}
+	p.cond.Broadcast()
+}
+
+// Serves the requests of a GAHTTPEvaluator. The genes of each request are
+// set in copies of Genome, whose score function computes the scores.
+type GAWorker struct {
+	Genome GAGenome
+}
+
+func NewGAWorker(genome GAGenome) *GAWorker {
+	return &GAWorker{genome}
+}
+
+func (w *GAWorker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(rw, "POST genes to score", http.StatusMethodNotAllowed)
+		return
+	}
+	var req gaEvaluateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := gaEvaluateResponse{Scores: make([]float64, len(req.Genes))}
+	for i, genes := range req.Genes {
+		g := w.Genome.Copy()
+		if err := setGeneJSON(g, genes); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp.Scores[i] = g.Score()
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(resp)
+}
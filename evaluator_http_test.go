@@ -0,0 +1,165 @@
+package ga
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// unscoredFloats returns n genomes without a score function, which panic
+// unless something else caches their score.
+func unscoredFloats(n int) GAGenomes {
+	pop := make(GAGenomes, n)
+	for i := range pop {
+		pop[i] = NewFloatGenome([]float64{float64(i), 1}, nil, 10, -10)
+	}
+	return pop
+}
+
+func TestHTTPEvaluator(t *testing.T) {
+	var requests int32
+	worker := NewGAWorker(NewFloatGenome(nil, sphere, 10, -10))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		worker.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	e := &GAHTTPEvaluator{Workers: []string{srv.URL, srv.URL}, Batch: 3}
+	pop := unscoredFloats(10)
+	if err := e.Evaluate(pop); err != nil {
+		t.Fatalf("Evaluate() = %v", err)
+	}
+	for i, g := range pop {
+		if want := float64(i*i + 1); g.Score() != want {
+			t.Errorf("genome %d score = %v; want %v", i, g.Score(), want)
+		}
+	}
+	if requests != 4 {
+		t.Errorf("Evaluate() sent %d requests for 10 genomes in batches of 3; want 4", requests)
+	}
+	// Scored genomes are not sent again.
+	if err := e.Evaluate(pop); err != nil || requests != 4 {
+		t.Errorf("Evaluate() of a scored population = %v after %d requests; want nil after 4", err, requests)
+	}
+}
+
+func TestHTTPEvaluatorFailures(t *testing.T) {
+	worker := NewGAWorker(NewFloatGenome(nil, sphere, 10, -10))
+	good := httptest.NewServer(worker)
+	defer good.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "simulator crashed", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		worker.ServeHTTP(w, r)
+	}))
+	defer slow.Close()
+	dead := httptest.NewServer(worker)
+	dead.Close()
+
+	// Failed requests move on to the good worker.
+	e := &GAHTTPEvaluator{Workers: []string{broken.URL, dead.URL, slow.URL, good.URL},
+		Batch: 2, Retries: 3, Timeout: 50 * time.Millisecond}
+	pop := unscoredFloats(8)
+	if err := e.Evaluate(pop); err != nil {
+		t.Fatalf("Evaluate() with one good worker = %v", err)
+	}
+	for i, g := range pop {
+		if want := float64(i*i + 1); g.Score() != want {
+			t.Errorf("genome %d score = %v; want %v", i, g.Score(), want)
+		}
+	}
+
+	e = &GAHTTPEvaluator{Workers: []string{broken.URL, slow.URL}, Retries: 1, Timeout: 50 * time.Millisecond}
+	if err := e.Evaluate(unscoredFloats(4)); err == nil {
+		t.Errorf("Evaluate() without a good worker = nil; want error")
+	}
+	e.Local = true
+	pop = GAGenomes{NewFloatGenome([]float64{2, 3}, sphere, 10, -10)}
+	if err := e.Evaluate(pop); err != nil || pop[0].Score() != 13 {
+		t.Errorf("Evaluate() with Local = %v, score %v; want nil, 13", err, pop[0].Score())
+	}
+	// Genomes without a score function can not be scored locally.
+	if err := e.Evaluate(unscoredFloats(2)); err == nil {
+		t.Errorf("Evaluate() with Local of genomes without score function = nil; want error")
+	}
+}
+
+func TestGAHTTPEvaluator(t *testing.T) {
+	srv := httptest.NewServer(NewGAWorker(NewFloatGenome(nil, sphere, 10, -10)))
+	defer srv.Close()
+	ga := NewGA(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		Evaluator:   NewGAHTTPEvaluator(srv.URL, srv.URL, srv.URL),
+		PMutate:     0.5,
+		PBreed:      0.5})
+	// The genomes of the GA have no score function of their own.
+	ga.Init(20, NewFloatGenome(make([]float64, 4), nil, 10, -10))
+	start := ga.Best().Score()
+	ga.Optimize(20)
+	if best := ga.Best().Score(); best >= start {
+		t.Errorf("best score %v after 20 generations; want below %v", best, start)
+	}
+}
+
+// Tests that GA and GAAsync stop with an error, instead of panicking, when
+// their workers stop answering, and keep a scored population.
+func TestEvaluatorError(t *testing.T) {
+	var requests int32
+	worker := NewGAWorker(NewFloatGenome(nil, sphere, 10, -10))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 10 {
+			http.Error(w, "gone", http.StatusInternalServerError)
+			return
+		}
+		worker.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	parameter := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		Evaluator:   NewGAHTTPEvaluator(srv.URL),
+		PMutate:     0.5,
+		PBreed:      0.5}
+
+	ga := NewGA(parameter)
+	ga.Init(20, NewFloatGenome(make([]float64, 4), nil, 10, -10))
+	ga.Optimize(20)
+	if ga.Err() == nil || ga.Generation() >= 20 {
+		t.Errorf("GA stopped at generation %d with %v; want an error before 20", ga.Generation(), ga.Err())
+	}
+	for _, g := range ga.pop {
+		if !g.(GAScoreSetter).HasScore() {
+			t.Fatalf("GA population holds unscored genome %v after the error", g)
+		}
+	}
+	ga.Best()
+	// A failed Init leaves no hall of fame of the run before.
+	ga.Parameter.HallOfFame = 3
+	ga.hof = NewGAHallOfFame(3)
+	ga.Init(20, NewFloatGenome(make([]float64, 4), nil, 10, -10))
+	if ga.Err() == nil || ga.HallOfFame() != nil {
+		t.Errorf("failed Init() left Err() = %v, HallOfFame() = %v; want an error and none", ga.Err(), ga.HallOfFame())
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	async := NewGAAsync(parameter, 4)
+	async.Init(20, NewFloatGenome(make([]float64, 4), nil, 10, -10))
+	async.Optimize(1000)
+	if async.Err() == nil {
+		t.Errorf("GAAsync.Err() = nil after its workers failed")
+	}
+	async.Best()
+}
@@ -0,0 +1,244 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Evaluation by external processes, for score functions written in other
+languages. The GAProcessEvaluator writes the genes of every genome as a JSON
+array on a line of the standard input of the process, which answers with the
+score as a number on a line of its standard output, in the same order.
+Processes are started when first needed and kept running between
+generations. A process that crashes, stops answering in time or answers
+with something else than a number is killed and a new one is started.
+
+A score process in Python:
+
+	import json, sys
+	for line in sys.stdin:
+		genes = json.loads(line)
+		print(sum(x * x for x in genes), flush=True)
+*/
+
+package ga
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type GAProcessEvaluator struct {
+	// Command and arguments starting a process
+	Command []string
+	// Environment of the processes, nil uses the one of this process
+	Env []string
+	// Where the standard error of the processes goes, nil discards it
+	Stderr io.Writer
+	// Genomes written to a process before reading its scores, 0 spreads
+	// them evenly over the processes
+	Batch int
+	// Number of processes running at once, 0 runs one
+	Processes int
+	// Time a process may take for a batch, 0 waits forever
+	Timeout time.Duration
+	// Number of times a batch is sent to a new process after its process
+	// failed
+	Retries int
+	// Score the genomes of a batch that failed every retry locally instead
+	// of returning an error, if they all have score functions
+	Local bool
+
+	once  sync.Once
+	slots chan *gaProcess
+}
+
+func NewGAProcessEvaluator(command ...string) *GAProcessEvaluator {
+	return &GAProcessEvaluator{Command: command}
+}
+
+type gaProcess struct {
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	out    *bufio.Reader
+	killed sync.Once
+}
+
+func (e *GAProcessEvaluator) processes() int {
+	if e.Processes < 1 {
+		return 1
+	}
+	return e.Processes
+}
+
+// Evaluate sends the genomes without a score to the processes in batches.
+func (e *GAProcessEvaluator) Evaluate(pop GAGenomes) error {
+	todo := unscored(pop)
+	if len(todo) == 0 {
+		return nil
+	}
+	if len(e.Command) == 0 {
+		return fmt.Errorf("ga: %s has no command", e)
+	}
+	e.once.Do(func() {
+		// A nil slot is a process not started yet.
+		e.slots = make(chan *gaProcess, e.processes())
+		for i := 0; i < e.processes(); i++ {
+			e.slots <- nil
+		}
+	})
+	batch := e.Batch
+	if batch <= 0 {
+		batch = (len(todo) + e.processes() - 1) / e.processes()
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, (len(todo)+batch-1)/batch)
+	for i := 0; i < len(todo); i += batch {
+		j := i + batch
+		if j > len(todo) {
+			j = len(todo)
+		}
+		wg.Add(1)
+		go func(b GAGenomes) {
+			defer wg.Done()
+			errs <- e.batch(b)
+		}(todo[i:j])
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch evaluates the genomes of b on a free process, starting new
+// processes for failed ones. The genes are encoded before any process is
+// used, a process would wait for genomes that can not be encoded.
+func (e *GAProcessEvaluator) batch(b GAGenomes) error {
+	var lines bytes.Buffer
+	for _, g := range b {
+		genes, err := geneJSON(g)
+		if err != nil {
+			return err
+		}
+		lines.Write(genes)
+		lines.WriteByte('\n')
+	}
+	var err error
+	for try := 0; try <= e.Retries; try++ {
+		p := <-e.slots
+		if p == nil {
+			if p, err = e.start(); err != nil {
+				e.slots <- nil
+				continue
+			}
+		}
+		var scores []float64
+		if scores, err = e.scores(p, lines.Bytes(), len(b)); err == nil {
+			e.slots <- p
+			for i, g := range b {
+				g.(GAScoreSetter).SetScore(scores[i])
+			}
+			return nil
+		}
+		// The slot is freed for a new process, the failed one must not keep
+		// running.
+		p.stop()
+		e.slots <- nil
+	}
+	if e.Local && scorable(b) {
+		for _, g := range b {
+			g.Score()
+		}
+		return nil
+	}
+	return err
+}
+
+func (e *GAProcessEvaluator) start() (*gaProcess, error) {
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+	cmd.Env = e.Env
+	cmd.Stderr = e.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &gaProcess{cmd: cmd, in: in, out: bufio.NewReader(out)}, nil
+}
+
+// scores writes the lines of genes of n genomes to the process while reading
+// its scores, so neither side blocks on a full pipe.
+func (e *GAProcessEvaluator) scores(p *gaProcess, lines []byte, n int) ([]float64, error) {
+	if e.Timeout > 0 {
+		t := time.AfterFunc(e.Timeout, p.kill)
+		defer t.Stop()
+	}
+	written := make(chan error, 1)
+	go func() {
+		_, err := p.in.Write(lines)
+		written <- err
+	}()
+	scores := make([]float64, n)
+	for i := range scores {
+		line, err := p.out.ReadString('\n')
+		if err != nil {
+			p.kill()
+			<-written
+			return nil, fmt.Errorf("ga: score process %s: %v", e.Command[0], err)
+		}
+		if scores[i], err = strconv.ParseFloat(strings.TrimSpace(line), 64); err != nil {
+			p.kill()
+			<-written
+			return nil, fmt.Errorf("ga: score process %s answered %q", e.Command[0], line)
+		}
+	}
+	return scores, <-written
+}
+
+// kill kills the process, it may be called more than once and while the
+// process is used.
+func (p *gaProcess) kill() {
+	p.killed.Do(func() {
+		p.in.Close()
+		p.cmd.Process.Kill()
+	})
+}
+
+// stop kills the process and waits for it to exit.
+func (p *gaProcess) stop() {
+	p.kill()
+	p.cmd.Wait()
+}
+
+// Close stops the processes. It must not be called during Evaluate, a
+// later Evaluate starts new processes.
+func (e *GAProcessEvaluator) Close() error {
+	if e.slots == nil {
+		return nil
+	}
+	for i := 0; i < e.processes(); i++ {
+		if p := <-e.slots; p != nil {
+			p.stop()
+		}
+	}
+	for i := 0; i < e.processes(); i++ {
+		e.slots <- nil
+	}
+	return nil
+}
+
+func (e *GAProcessEvaluator) String() string { return "GAProcessEvaluator" }
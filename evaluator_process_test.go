@@ -0,0 +1,166 @@
+package ga
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestScoreProcess is the score process started by the GAProcessEvaluator
+// tests. GA_SCORE_PROCESS=crash makes it exit after 3 genomes, =hang stop
+// answering after 3 genomes and =garbage answer with words. With
+// GA_SCORE_PIDS set it leaves a file named after its process id in that
+// directory.
+func TestScoreProcess(t *testing.T) {
+	mode := os.Getenv("GA_SCORE_PROCESS")
+	if mode == "" {
+		t.Skip("started by the GAProcessEvaluator tests")
+	}
+	if dir := os.Getenv("GA_SCORE_PIDS"); dir != "" {
+		os.WriteFile(filepath.Join(dir, strconv.Itoa(os.Getpid())), nil, 0666)
+	}
+	in := bufio.NewScanner(os.Stdin)
+	for n := 1; in.Scan(); n++ {
+		switch {
+		case mode == "crash" && n > 3:
+			os.Exit(3)
+		case mode == "hang" && n > 3:
+			time.Sleep(time.Hour)
+		case mode == "garbage":
+			fmt.Println("no idea")
+			continue
+		}
+		var genes []float64
+		if err := json.Unmarshal(in.Bytes(), &genes); err != nil {
+			os.Exit(2)
+		}
+		var s float64
+		for _, x := range genes {
+			s += x * x
+		}
+		fmt.Println(s)
+	}
+	os.Exit(0)
+}
+
+func scoreProcess(mode string) *GAProcessEvaluator {
+	e := NewGAProcessEvaluator(os.Args[0], "-test.run=^TestScoreProcess$")
+	e.Env = append(os.Environ(), "GA_SCORE_PROCESS="+mode)
+	return e
+}
+
+func checkScores(t *testing.T, pop GAGenomes) {
+	for i, g := range pop {
+		if want := float64(i*i + 1); g.Score() != want {
+			t.Errorf("genome %d score = %v; want %v", i, g.Score(), want)
+		}
+	}
+}
+
+func TestProcessEvaluator(t *testing.T) {
+	e := scoreProcess("ok")
+	e.Processes, e.Batch = 3, 4
+	defer e.Close()
+	for i := 0; i < 3; i++ {
+		pop := unscoredFloats(20)
+		if err := e.Evaluate(pop); err != nil {
+			t.Fatalf("Evaluate() = %v", err)
+		}
+		checkScores(t, pop)
+	}
+}
+
+func TestProcessEvaluatorRecovery(t *testing.T) {
+	// Every process dies on its fourth genome.
+	e := scoreProcess("crash")
+	e.Batch, e.Retries = 2, 10
+	defer e.Close()
+	pop := unscoredFloats(10)
+	if err := e.Evaluate(pop); err != nil {
+		t.Fatalf("Evaluate() with crashing processes = %v", err)
+	}
+	checkScores(t, pop)
+
+	e = scoreProcess("hang")
+	e.Batch, e.Retries, e.Timeout = 2, 10, 200*time.Millisecond
+	defer e.Close()
+	pop = unscoredFloats(10)
+	if err := e.Evaluate(pop); err != nil {
+		t.Fatalf("Evaluate() with hanging processes = %v", err)
+	}
+	checkScores(t, pop)
+
+	e = scoreProcess("garbage")
+	e.Retries = 2
+	defer e.Close()
+	if err := e.Evaluate(unscoredFloats(3)); err == nil {
+		t.Errorf("Evaluate() of a process answering words = nil; want error")
+	}
+	e.Local = true
+	pop = GAGenomes{NewFloatGenome([]float64{2, 3}, sphere, 10, -10)}
+	if err := e.Evaluate(pop); err != nil || pop[0].Score() != 13 {
+		t.Errorf("Evaluate() with Local = %v, score %v; want nil, 13", err, pop[0].Score())
+	}
+	if err := e.Evaluate(unscoredFloats(2)); err == nil {
+		t.Errorf("Evaluate() with Local of genomes without score function = nil; want error")
+	}
+
+	e = NewGAProcessEvaluator("/nonexistent/score-process")
+	if err := e.Evaluate(unscoredFloats(3)); err == nil {
+		t.Errorf("Evaluate() with a missing command = nil; want error")
+	}
+}
+
+func TestProcessEvaluatorClose(t *testing.T) {
+	// Batches that failed on a process retry on the processes of the other
+	// batches, which must all be stopped by Close.
+	dir := t.TempDir()
+	e := scoreProcess("crash")
+	e.Env = append(e.Env, "GA_SCORE_PIDS="+dir)
+	e.Processes, e.Batch, e.Retries = 3, 2, 20
+	for i := 0; i < 3; i++ {
+		pop := unscoredFloats(20)
+		if err := e.Evaluate(pop); err != nil {
+			t.Fatalf("Evaluate() with crashing processes = %v", err)
+		}
+		checkScores(t, pop)
+	}
+	e.Close()
+	pids, err := os.ReadDir(dir)
+	if err != nil || len(pids) == 0 {
+		t.Fatalf("no score processes recorded: %v", err)
+	}
+	for _, f := range pids {
+		pid, _ := strconv.Atoi(f.Name())
+		if p, err := os.FindProcess(pid); err == nil && p.Signal(syscall.Signal(0)) == nil {
+			t.Errorf("score process %d still runs after Close", pid)
+			p.Kill()
+		}
+	}
+}
+
+// Genome that is not built in, so its genes have no JSON form.
+type opaqueGenome struct{ *GAFloatGenome }
+
+func TestProcessEvaluatorEncodeError(t *testing.T) {
+	e := scoreProcess("ok")
+	defer e.Close()
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Evaluate(GAGenomes{opaqueGenome{NewFloatGenome([]float64{1, 2}, nil, 10, -10)}})
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Evaluate() of a genome without JSON genes = nil; want error")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Evaluate() of a genome without JSON genes did not return")
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"math/rand"
 	"os"
 	"runtime/pprof"
 	"time"
@@ -404,7 +403,7 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	rand.Seed(time.Now().UTC().UnixNano())
+	ga.Seed(time.Now().UTC().UnixNano())
 	experiments := []*Experiment{}
 	for e := range Experiments {
 		if *set == "all" || *set == Experiments[e].set {
@@ -11,7 +11,6 @@ import (
 	"fmt"
 	"github.com/thoj/go-galib"
 	"math"
-	"math/rand"
 	"time"
 )
 
@@ -42,7 +41,7 @@ func rosenbrock(g *ga.GAFloatGenome) float64 {
 }
 
 func main() {
-	rand.Seed(time.Now().UTC().UnixNano())
+	ga.Seed(time.Now().UTC().UnixNano())
 
 	param := ga.GAParameter{
 		Initializer: new(ga.GARandomInitializer),
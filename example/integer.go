@@ -9,7 +9,6 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	ga "github.com/pointlander/go-galib"
@@ -31,7 +30,7 @@ func score(g *ga.GAIntGenome) float64 {
 }
 
 func main() {
-	rand.Seed(time.Now().UTC().UnixNano())
+	ga.Seed(time.Now().UTC().UnixNano())
 
 	m := ga.NewMultiMutator()
 	msh := new(ga.GAShiftMutator)
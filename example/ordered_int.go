@@ -10,7 +10,6 @@ package main
 import (
 	"fmt"
 	"github.com/thoj/go-galib"
-	"math/rand"
 	"time"
 )
 
@@ -27,7 +26,7 @@ func score(g *ga.GAOrderedIntGenome) float64 {
 }
 
 func main() {
-	rand.Seed(time.Now().UTC().UnixNano())
+	ga.Seed(time.Now().UTC().UnixNano())
 
 	m := ga.NewMultiMutator()
 	msh := new(ga.GAShiftMutator)
@@ -11,7 +11,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"runtime/pprof"
 	"time"
@@ -104,7 +103,7 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	rand.Seed(time.Now().UTC().UnixNano())
+	ga.Seed(time.Now().UTC().UnixNano())
 	generations, scores := ga.Sketch{}, ga.Sketch{}
 	for i := 0; i < SAMPLES; i++ {
 		g, s := optimize(false)
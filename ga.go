@@ -10,7 +10,7 @@ package ga
 
 import (
 	"fmt"
-	"math/rand"
+	"math"
 	"sort"
 )
 
@@ -19,6 +19,15 @@ type GAParameter struct {
 	PBreed float64
 	//Chance of mutation
 	PMutate float64
+	//Number of best genomes always carried over to the next generation
+	Elite int
+	//Number of best distinct genomes ever seen kept in the hall of fame
+	HallOfFame int
+	//Diversity measure recorded in the statistics, for example Diversity
+	Diversity func(pop GAGenomes) float64
+	//Reseed part of the population when diversity drops, uses Diversity
+	//or the Diversity function if not set
+	Restart *GARestart
 
 	// Initializer, Selector, Mutator, Breeder Objects this GA will use
 	Initializer GAInitializer
@@ -26,11 +35,21 @@ type GAParameter struct {
 	Mutator     GAMutator
 	Breeder     GABreeder
 	Neural      GANeural
+	// Survivor selection, nil keeps the best of parents and children
+	Replacement GAReplacement
+	// Computes the scores of new genomes in batches, nil lets every genome
+	// compute its own score
+	Evaluator GAEvaluator
 }
 
 type GA struct {
-	pop     GAGenomes
-	popsize int
+	pop        GAGenomes
+	popsize    int
+	generation int
+	schedules  []gaScheduled
+	history    []GAStats
+	hof        *GAHallOfFame
+	err        error
 
 	Parameter GAParameter
 	Parallel  bool
@@ -50,43 +69,96 @@ func (ga *GA) String() string {
 		ga.Parameter.Breeder)
 }
 
+// Init creates the population. If the Evaluator fails the population is
+// left unscored and Err returns the error.
 func (ga *GA) Init(popsize int, i GAGenome) {
+	ga.err = nil
 	ga.pop = ga.Parameter.Initializer.InitPop(i, popsize)
 	ga.popsize = popsize
+	ga.generation = 0
+	ga.history = nil
+	ga.hof = nil
+	if !ga.evaluate(ga.pop) {
+		return
+	}
+	sort.Sort(ga.pop)
+	if ga.Parameter.HallOfFame > 0 {
+		ga.hof = NewGAHallOfFame(ga.Parameter.HallOfFame)
+		ga.hof.Update(ga.pop)
+	}
+	ga.record()
+}
+
+// Schedule lets s drive the parameter target points to, for example
+// &ga.Parameter.PMutate or the StdDev of a GAGaussianMutator. The value is set
+// at the start of every generation and shows up in the statistics as name.
+func (ga *GA) Schedule(name string, target *float64, s GASchedule) {
+	ga.schedules = append(ga.schedules, gaScheduled{name, target, s})
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GA) Generation() int { return ga.generation }
+
+func (ga *GA) applySchedules() {
+	best := math.Inf(1)
+	if len(ga.history) > 0 {
+		best = ga.history[len(ga.history)-1].Best
+	}
+	for _, s := range ga.schedules {
+		*s.target = s.schedule.Value(ga.generation, best)
+	}
 }
 
+// Optimize runs gen generations. It stops early if the Evaluator fails, the
+// population is then left as it was before the failed generation and Err
+// returns the error.
 func (ga *GA) Optimize(gen int) {
-	for i := 0; i < gen; i++ {
+	for i := 0; i < gen && ga.err == nil; i++ {
+		var saved GAGenomes
+		if ga.Parameter.Evaluator != nil {
+			saved = append(saved, ga.pop...)
+		}
+		ga.applySchedules()
+		elite := ga.elite()
 		l, pop := len(ga.pop), ga.pop // Do not try to breed/mutate new in this gen
+		if ga.Parameter.Replacement != nil {
+			// Children replace genomes in place, keep the parents apart.
+			pop = make(GAGenomes, l)
+			copy(pop, ga.pop)
+		}
 		if ga.Parameter.Neural != nil {
 			ga.Parameter.Neural.Train(ga.pop, ga.Parameter.Selector)
 		}
-		for p := 0; p < l; p++ {
+		for p := 0; p < l && ga.err == nil; p++ {
 			//Breed two inviduals selected with selector.
-			if ga.Parameter.Breeder != nil && ga.Parameter.PBreed > rand.Float64() {
-				children := make(GAGenomes, 2)
-				children[0], children[1] = ga.Parameter.Breeder.Breed(
+			if ga.Parameter.Breeder != nil && ga.Parameter.PBreed > rng.Float64() {
+				parents := GAGenomes{
 					ga.Parameter.Selector.SelectOne(pop),
-					ga.Parameter.Selector.SelectOne(pop))
-
-				ga.pop = AppendGenomes(ga.pop, children)
+					ga.Parameter.Selector.SelectOne(pop)}
+				children := make(GAGenomes, 2)
+				children[0], children[1] = ga.Parameter.Breeder.Breed(parents[0], parents[1])
+				ga.offspring(parents, children)
 			}
 			//Mutate
-			if ga.Parameter.Mutator != nil && ga.Parameter.PMutate > rand.Float64() {
+			if ga.Parameter.Mutator != nil && ga.Parameter.PMutate > rng.Float64() {
 				children := make(GAGenomes, 1)
 				children[0] = ga.Parameter.Mutator.Mutate(pop[p])
-				ga.pop = AppendGenomes(ga.pop, children)
+				ga.offspring(pop[p:p+1], children)
 			}
 			//Neural
-			//if ga.Parameter.Neural != nil && ga.Parameter.PMutate > rand.Float64() {
+			//if ga.Parameter.Neural != nil && ga.Parameter.PMutate > rng.Float64() {
 			if ga.Parameter.Neural != nil {
 				for i := 0; i < 2; i++ {
 					morphed := make(GAGenomes, 1)
 					morphed[0] = ga.Parameter.Neural.Morph(pop[p])
-					ga.pop = AppendGenomes(ga.pop, morphed)
+					ga.offspring(pop[p:p+1], morphed)
 				}
 			}
 		}
+		if !ga.evaluate(ga.pop) {
+			ga.pop = saved
+			return
+		}
 		//cleanup remove some from pop
 		// this should probably use a type of selector
 		if ga.Parallel {
@@ -96,12 +168,146 @@ func (ga *GA) Optimize(gen int) {
 		} else {
 			sort.Sort(ga.pop)
 		}
-		ga.pop = ga.pop[0:ga.popsize]
+		if len(ga.pop) > ga.popsize {
+			ga.pop = ga.pop[0:ga.popsize]
+		}
+		ga.keepElite(elite)
+		if ga.hof != nil {
+			ga.hof.Update(ga.pop)
+		}
+		ga.generation++
+		ga.record()
+		ga.restart()
+	}
+}
+
+// offspring adds children made from parents to the population, either by
+// appending them for truncation at the end of the generation or through the
+// Replacement.
+func (ga *GA) offspring(parents, children GAGenomes) {
+	if ga.Parameter.Replacement != nil {
+		if ga.evaluate(children) {
+			ga.Parameter.Replacement.Replace(ga.pop, parents, children)
+		}
+		return
+	}
+	ga.pop = AppendGenomes(ga.pop, children)
+}
+
+// evaluate has the Evaluator compute the scores of pop. It returns false
+// and keeps the error for Err if the Evaluator fails or failed before.
+func (ga *GA) evaluate(pop GAGenomes) bool {
+	if ga.err != nil {
+		return false
+	}
+	if ga.Parameter.Evaluator == nil {
+		return true
+	}
+	ga.err = ga.Parameter.Evaluator.Evaluate(pop)
+	return ga.err == nil
+}
+
+// Err returns the error of the Evaluator that stopped the GA, nil if there
+// was none since Init.
+func (ga *GA) Err() error { return ga.err }
+
+// elite returns the Parameter.Elite best genomes of the population.
+func (ga *GA) elite() GAGenomes {
+	n := ga.Parameter.Elite
+	if n <= 0 {
+		return nil
+	}
+	if n > len(ga.pop) {
+		n = len(ga.pop)
+	}
+	sort.Sort(ga.pop)
+	elite := make(GAGenomes, n)
+	copy(elite, ga.pop)
+	return elite
+}
+
+// keepElite puts the elite genomes that did not survive the generation back
+// in place of the worst genomes of the sorted population.
+func (ga *GA) keepElite(elite GAGenomes) {
+	if len(elite) == 0 {
+		return
+	}
+	survived := make(map[GAGenome]bool, len(ga.pop))
+	for _, g := range ga.pop {
+		survived[g] = true
+	}
+	isElite := make(map[GAGenome]bool, len(elite))
+	for _, e := range elite {
+		isElite[e] = true
+	}
+	j, changed := len(ga.pop)-1, false
+	for _, e := range elite {
+		if survived[e] {
+			continue
+		}
+		for j >= 0 && isElite[ga.pop[j]] {
+			j--
+		}
+		if j < 0 {
+			break
+		}
+		ga.pop[j] = e
+		j--
+		changed = true
+	}
+	if changed {
+		sort.Sort(ga.pop)
+	}
+}
+
+// restart reseeds the worst part of the population from the initializer when
+// the diversity recorded for this generation is below the threshold.
+func (ga *GA) restart() {
+	r := ga.Parameter.Restart
+	if r == nil || len(ga.pop) == 0 {
+		return
+	}
+	s := &ga.history[len(ga.history)-1]
+	if s.Diversity >= r.Threshold {
+		return
+	}
+	// The elite, and at least the best genome, are kept.
+	keep := ga.Parameter.Elite
+	if keep < 1 {
+		keep = 1
+	}
+	n := int(r.Fraction * float64(len(ga.pop)))
+	if n > len(ga.pop)-keep {
+		n = len(ga.pop) - keep
+	}
+	if n <= 0 {
+		return
+	}
+	initializer := r.Initializer
+	if initializer == nil {
+		initializer = ga.Parameter.Initializer
+	}
+	// Some initializers randomize the genome they are given.
+	fresh := initializer.InitPop(ga.pop[0].Copy(), n)
+	if !ga.evaluate(fresh) {
+		return
+	}
+	copy(ga.pop[len(ga.pop)-n:], fresh)
+	sort.Sort(ga.pop)
+	s.Restarted = n
+}
+
+// HallOfFame returns the Parameter.HallOfFame best distinct genomes seen since
+// Init, best first.
+func (ga *GA) HallOfFame() GAGenomes {
+	if ga.hof == nil {
+		return nil
 	}
+	return ga.hof.Genomes()
 }
 
 func (ga *GA) OptimizeUntil(stop func(best GAGenome) bool) {
-	for !stop(ga.Best()) {
+	for ga.err == nil && !stop(ga.Best()) {
 		ga.Optimize(1)
 	}
 }
@@ -0,0 +1,231 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Asynchronous steady-state Genetic Algorithm. Workers keep picking parents,
+making and scoring children and putting them in the shared population, with
+no generations to wait for, so slow scores only hold up their own worker.
+*/
+
+package ga
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type GAAsync struct {
+	// Neural is not used. Without a Replacement a child takes the place of
+	// the worst genome if it is better.
+	Parameter GAParameter
+	// Number of goroutines making children, 0 uses one for every CPU
+	Workers int
+
+	mu          sync.Mutex
+	pop         GAGenomes
+	popsize     int
+	evaluations int
+	hof         *GAHallOfFame
+	err         error
+}
+
+func NewGAAsync(parameter GAParameter, workers int) *GAAsync {
+	return &GAAsync{Parameter: parameter, Workers: workers}
+}
+
+func (ga *GAAsync) String() string {
+	return fmt.Sprintf("Initializer = %s, Selector = %s, Mutator = %s Breeder = %s",
+		ga.Parameter.Initializer,
+		ga.Parameter.Selector,
+		ga.Parameter.Mutator,
+		ga.Parameter.Breeder)
+}
+
+// Init creates the population. If the Evaluator fails the population is
+// left unscored and Err returns the error.
+func (ga *GAAsync) Init(popsize int, i GAGenome) {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	ga.pop = ga.Parameter.Initializer.InitPop(i, popsize)
+	ga.popsize = popsize
+	ga.evaluations = 0
+	ga.hof = nil
+	ga.err = nil
+	if e := ga.Parameter.Evaluator; e != nil {
+		if ga.err = e.Evaluate(ga.pop); ga.err != nil {
+			return
+		}
+	}
+	sort.Sort(ga.pop)
+	if ga.Parameter.HallOfFame > 0 {
+		ga.hof = NewGAHallOfFame(ga.Parameter.HallOfFame)
+		ga.hof.Update(ga.pop)
+	}
+}
+
+// Optimize picks parents n times, making one child from a single parent or
+// two by breeding each time.
+func (ga *GAAsync) Optimize(n int) {
+	made := 0
+	ga.run(func() bool {
+		if made >= n {
+			return false
+		}
+		made++
+		return true
+	})
+}
+
+// OptimizeUntil makes children until stop returns true for the best genome,
+// which is checked before every pick of parents.
+func (ga *GAAsync) OptimizeUntil(stop func(best GAGenome) bool) {
+	ga.run(func() bool { return !stop(ga.pop[0]) })
+}
+
+// run lets the workers make children while more, called with the lock
+// held, returns true. The workers stop at the first error of an Evaluator,
+// which Err returns.
+func (ga *GAAsync) run(more func() bool) {
+	workers := ga.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		// Every worker has its own operators with state.
+		go func(p GAParameter) {
+			defer wg.Done()
+			for ga.step(&p, more) {
+			}
+		}(ga.Parameter.clone())
+	}
+	wg.Wait()
+}
+
+// step makes, scores and puts in one batch of children using the operators
+// of p. It returns false when more does or an Evaluator fails.
+func (ga *GAAsync) step(p *GAParameter, more func() bool) bool {
+	ga.mu.Lock()
+	if ga.err != nil || !more() {
+		ga.mu.Unlock()
+		return false
+	}
+	var parents GAGenomes
+	breed := p.Breeder != nil && p.PBreed > rng.Float64()
+	if breed {
+		parents = GAGenomes{p.Selector.SelectOne(ga.pop), p.Selector.SelectOne(ga.pop)}
+	} else {
+		parents = GAGenomes{p.Selector.SelectOne(ga.pop)}
+	}
+	ga.mu.Unlock()
+
+	var children GAGenomes
+	if breed {
+		a, b := p.Breeder.Breed(parents[0], parents[1])
+		children = GAGenomes{a, b}
+	} else {
+		children = GAGenomes{parents[0]}
+	}
+	changed := breed
+	for i := range children {
+		if p.Mutator != nil && p.PMutate > rng.Float64() {
+			children[i] = p.Mutator.Mutate(children[i])
+			changed = true
+		}
+	}
+	if !changed {
+		// Nothing new to put in the population.
+		return true
+	}
+	if p.Evaluator != nil {
+		if err := p.Evaluator.Evaluate(children); err != nil {
+			ga.mu.Lock()
+			if ga.err == nil {
+				ga.err = err
+			}
+			ga.mu.Unlock()
+			return false
+		}
+	}
+	for _, c := range children {
+		c.Score()
+	}
+
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	ga.evaluations += len(children)
+	if p.Replacement != nil {
+		p.Replacement.Replace(ga.pop, parents, children)
+		sort.Sort(ga.pop)
+	} else {
+		for _, c := range children {
+			ga.insert(c)
+		}
+	}
+	if ga.hof != nil {
+		ga.hof.Update(children)
+	}
+	return true
+}
+
+// insert puts c in the sorted population in place of the worst genome if c
+// is better.
+func (ga *GAAsync) insert(c GAGenome) {
+	s := c.Score()
+	if len(ga.pop) >= ga.popsize {
+		if s >= ga.pop[len(ga.pop)-1].Score() {
+			return
+		}
+		ga.pop = ga.pop[:len(ga.pop)-1]
+	}
+	i := sort.Search(len(ga.pop), func(i int) bool { return ga.pop[i].Score() > s })
+	ga.pop = append(ga.pop, nil)
+	copy(ga.pop[i+1:], ga.pop[i:])
+	ga.pop[i] = c
+}
+
+// Err returns the first error of an Evaluator since Init, nil if there was
+// none.
+func (ga *GAAsync) Err() error {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	return ga.err
+}
+
+// Evaluations returns the number of children scored since Init.
+func (ga *GAAsync) Evaluations() int {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	return ga.evaluations
+}
+
+func (ga *GAAsync) Best() GAGenome {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	return ga.pop[0]
+}
+
+// HallOfFame returns the Parameter.HallOfFame best distinct genomes seen since
+// Init, best first.
+func (ga *GAAsync) HallOfFame() GAGenomes {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	if ga.hof == nil {
+		return nil
+	}
+	return ga.hof.Genomes()
+}
+
+func (ga *GAAsync) PrintTop(n int) {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	if len(ga.pop) < n {
+		n = len(ga.pop)
+	}
+	for i := 0; i < n; i++ {
+		fmt.Printf("%2d: %s Score = %f\n", i, ga.pop[i], ga.pop[i].Score())
+	}
+}
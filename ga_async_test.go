@@ -0,0 +1,67 @@
+package ga
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestGAAsync(t *testing.T) {
+	m := NewMultiMutator()
+	m.Add(NewGAGaussianMutator(1, 0))
+	m.Add(new(GAPolynomialMutator))
+	// Some scores take much longer than others.
+	slow := func(g *GAFloatGenome) float64 {
+		if rng.Intn(10) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return sphere(g)
+	}
+	ga := NewGAAsync(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     m,
+		PMutate:     0.5,
+		PBreed:      0.5,
+		HallOfFame:  3}, 4)
+	ga.Init(30, NewFloatGenome(make([]float64, 5), slow, 10, -10))
+	start := ga.Best().Score()
+	ga.Optimize(2000)
+	if n := ga.Evaluations(); n < 1000 || n > 4000 {
+		t.Errorf("Evaluations() = %d after 2000 picks; want between 1000 and 4000", n)
+	}
+	if best := ga.Best().Score(); best >= start || best > 1 {
+		t.Errorf("best score %v after 2000 picks; want below 1 and %v", best, start)
+	}
+	if len(ga.pop) != 30 || !sort.IsSorted(ga.pop) {
+		t.Errorf("population of %d genomes, sorted %v; want 30 sorted", len(ga.pop), sort.IsSorted(ga.pop))
+	}
+	if h := ga.HallOfFame(); len(h) != 3 || h[0].Score() != ga.Best().Score() {
+		t.Errorf("HallOfFame() = %v; want 3 genomes led by the best", h)
+	}
+	if m.stats[0]+m.stats[1] != 0 {
+		t.Errorf("workers used the GAMultiMutator of the parameters instead of their own")
+	}
+
+	ga.OptimizeUntil(func(best GAGenome) bool { return best.Score() < 0.01 })
+	if best := ga.Best().Score(); best >= 0.01 {
+		t.Errorf("OptimizeUntil() stopped at score %v; want below 0.01", best)
+	}
+}
+
+func TestGAAsyncReplacement(t *testing.T) {
+	ga := NewGAAsync(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 3),
+		Breeder:     new(GAUniformBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		Replacement: new(GADeterministicCrowding),
+		PMutate:     0.3,
+		PBreed:      0.9}, 3)
+	ga.Init(20, NewFloatGenome(make([]float64, 3), sphere, 10, -10))
+	ga.Optimize(500)
+	if len(ga.pop) != 20 || !sort.IsSorted(ga.pop) {
+		t.Errorf("population of %d genomes, sorted %v; want 20 sorted", len(ga.pop), sort.IsSorted(ga.pop))
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Cellular Genetic Algorithm. Every genome lives in a cell of a grid whose
+edges wrap around, a torus, and only mates with genomes of its
+neighbourhood. Good genomes spread slowly over the grid, which keeps the
+population diverse for longer than a GA where any two genomes can mate.
+*/
+
+package ga
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Shape of the neighbourhood of a cell.
+type GANeighbourhood int
+
+const (
+	// Cells at most Radius steps away along the rows and columns, von
+	// Neumann neighbourhood.
+	GAVonNeumann GANeighbourhood = iota
+	// Cells in the square of side 2*Radius+1 around the cell, Moore
+	// neighbourhood.
+	GAMoore
+)
+
+// Order in which the cells of the grid are updated in a generation.
+type GAUpdate int
+
+const (
+	// Every cell is updated from the grid of the previous generation.
+	GASynchronous GAUpdate = iota
+	// Cells are updated in place row by row, later cells see the children
+	// of earlier ones.
+	GALineSweep
+	// Cells are updated in place in a random order drawn once.
+	GAFixedRandomSweep
+	// Cells are updated in place in a random order drawn every generation.
+	GANewRandomSweep
+	// As many cells as the grid has are drawn at random and updated in
+	// place, some more than once and some not at all.
+	GAUniformChoice
+)
+
+type GACellular struct {
+	// Selector picks a mate for a cell among its neighbourhood. The cell
+	// takes its child if the child is at least as good. Initializer, Breeder,
+	// Mutator, PBreed, PMutate and Evaluator are used as by GA, the other
+	// parameters are not.
+	Parameter GAParameter
+	// Size of the grid
+	Width, Height int
+	Neighbourhood GANeighbourhood
+	// Radius of the neighbourhood, 0 means 1
+	Radius int
+	Update GAUpdate
+
+	grid       GAGenomes
+	generation int
+	order      []int
+	err        error
+}
+
+func NewGACellular(parameter GAParameter, width, height int) *GACellular {
+	return &GACellular{Parameter: parameter, Width: width, Height: height}
+}
+
+func (ga *GACellular) String() string {
+	return fmt.Sprintf("Initializer = %s, Selector = %s, Mutator = %s Breeder = %s",
+		ga.Parameter.Initializer,
+		ga.Parameter.Selector,
+		ga.Parameter.Mutator,
+		ga.Parameter.Breeder)
+}
+
+// Init fills every cell of the grid with a genome from the initializer. If
+// the Evaluator fails the grid is left unscored and Err returns the error.
+func (ga *GACellular) Init(i GAGenome) {
+	if ga.Width < 1 || ga.Height < 1 {
+		panic("Grid is empty")
+	}
+	ga.err = nil
+	ga.generation = 0
+	ga.order = nil
+	ga.grid = ga.Parameter.Initializer.InitPop(i, ga.Width*ga.Height)
+	if !ga.evaluate(ga.grid) {
+		return
+	}
+}
+
+// evaluate has the Evaluator compute the scores of pop. It returns false
+// and keeps the error for Err if the Evaluator fails or failed before.
+func (ga *GACellular) evaluate(pop GAGenomes) bool {
+	if ga.err != nil {
+		return false
+	}
+	if ga.Parameter.Evaluator == nil {
+		return true
+	}
+	ga.err = ga.Parameter.Evaluator.Evaluate(pop)
+	return ga.err == nil
+}
+
+// Err returns the error of the Evaluator that stopped the GA, nil if there
+// was none since Init.
+func (ga *GACellular) Err() error { return ga.err }
+
+// Neighbours returns the cells of the neighbourhood of cell c, c itself
+// included. Cell x,y is number y*Width+x.
+func (ga *GACellular) Neighbours(c int) []int {
+	r := ga.Radius
+	if r < 1 {
+		r = 1
+	}
+	x, y := c%ga.Width, c/ga.Width
+	seen := make(map[int]bool)
+	var n []int
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if ga.Neighbourhood == GAVonNeumann && abs(dx)+abs(dy) > r {
+				continue
+			}
+			nx := ((x+dx)%ga.Width + ga.Width) % ga.Width
+			ny := ((y+dy)%ga.Height + ga.Height) % ga.Height
+			// A small grid may wrap onto the same cell twice.
+			if i := ny*ga.Width + nx; !seen[i] {
+				seen[i] = true
+				n = append(n, i)
+			}
+		}
+	}
+	return n
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// breed returns the two children of cell c of grid and a mate from its
+// neighbourhood, or nil if no breeding happens.
+func (ga *GACellular) breed(grid GAGenomes, c int) GAGenomes {
+	if ga.Parameter.Breeder == nil || ga.Parameter.PBreed <= rng.Float64() {
+		return nil
+	}
+	n := ga.Neighbours(c)
+	neighbourhood := make(GAGenomes, len(n))
+	for i, j := range n {
+		neighbourhood[i] = grid[j]
+	}
+	mate := ga.Parameter.Selector.SelectOne(neighbourhood)
+	a, b := ga.Parameter.Breeder.Breed(grid[c], mate)
+	return GAGenomes{a, b}
+}
+
+// child returns the better of the scored children bred, mutated, or the
+// mutated parent if nothing was bred. It returns nil if neither breeding
+// nor mutation happened.
+func (ga *GACellular) child(parent GAGenome, bred GAGenomes) GAGenome {
+	var child GAGenome
+	if bred != nil {
+		child = bred[0]
+		if bred[1].Score() < bred[0].Score() {
+			child = bred[1]
+		}
+	}
+	if ga.Parameter.Mutator != nil && ga.Parameter.PMutate > rng.Float64() {
+		if child == nil {
+			child = parent
+		}
+		child = ga.Parameter.Mutator.Mutate(child)
+	}
+	return child
+}
+
+// Optimize runs gen generations. It stops early if the Evaluator fails,
+// leaving the cells updated so far, and Err returns the error. With
+// GASynchronous updates the Evaluator scores all children bred in a
+// generation at once and then all mutated ones, the other updates score the
+// children of every cell before moving on to the next.
+func (ga *GACellular) Optimize(gen int) {
+	for i := 0; i < gen && ga.err == nil; i++ {
+		n := len(ga.grid)
+		if ga.Update == GASynchronous {
+			next := make(GAGenomes, n)
+			copy(next, ga.grid)
+			bred := make([]GAGenomes, n)
+			var all GAGenomes
+			for c := 0; c < n; c++ {
+				bred[c] = ga.breed(ga.grid, c)
+				all = append(all, bred[c]...)
+			}
+			if !ga.evaluate(all) {
+				return
+			}
+			var children GAGenomes
+			var cells []int
+			for c := 0; c < n; c++ {
+				if child := ga.child(ga.grid[c], bred[c]); child != nil {
+					children = append(children, child)
+					cells = append(cells, c)
+				}
+			}
+			if !ga.evaluate(children) {
+				return
+			}
+			for i, c := range cells {
+				if children[i].Score() <= next[c].Score() {
+					next[c] = children[i]
+				}
+			}
+			ga.grid = next
+		} else {
+			for _, c := range ga.sweep() {
+				bred := ga.breed(ga.grid, c)
+				if bred != nil && !ga.evaluate(bred) {
+					return
+				}
+				if child := ga.child(ga.grid[c], bred); child != nil {
+					if !ga.evaluate(GAGenomes{child}) {
+						return
+					}
+					if child.Score() <= ga.grid[c].Score() {
+						ga.grid[c] = child
+					}
+				}
+			}
+		}
+		ga.generation++
+	}
+}
+
+// sweep returns the cells to update in this generation, in order.
+func (ga *GACellular) sweep() []int {
+	n := len(ga.grid)
+	switch ga.Update {
+	case GAFixedRandomSweep:
+		if len(ga.order) != n {
+			ga.order = rng.Perm(n)
+		}
+		return ga.order
+	case GANewRandomSweep:
+		return rng.Perm(n)
+	case GAUniformChoice:
+		order := make([]int, n)
+		for i := range order {
+			order[i] = rng.Intn(n)
+		}
+		return order
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+func (ga *GACellular) OptimizeUntil(stop func(best GAGenome) bool) {
+	for ga.err == nil && !stop(ga.Best()) {
+		ga.Optimize(1)
+	}
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GACellular) Generation() int { return ga.generation }
+
+// Cell returns the genome in cell x,y.
+func (ga *GACellular) Cell(x, y int) GAGenome { return ga.grid[y*ga.Width+x] }
+
+func (ga *GACellular) Best() GAGenome {
+	best := ga.grid[0]
+	for _, g := range ga.grid[1:] {
+		if g.Score() < best.Score() {
+			best = g
+		}
+	}
+	return best
+}
+
+func (ga *GACellular) PrintTop(n int) {
+	sorted := make(GAGenomes, len(ga.grid))
+	copy(sorted, ga.grid)
+	sort.Sort(sorted)
+	if len(sorted) < n {
+		n = len(sorted)
+	}
+	for i := 0; i < n; i++ {
+		fmt.Printf("%2d: %s Score = %f\n", i, sorted[i], sorted[i].Score())
+	}
+}
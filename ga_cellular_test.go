@@ -0,0 +1,129 @@
+package ga
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCellularNeighbours(t *testing.T) {
+	ga := NewGACellular(GAParameter{}, 10, 8)
+	tests := []struct {
+		n      GANeighbourhood
+		radius int
+		size   int
+	}{
+		{GAVonNeumann, 0, 5},
+		{GAVonNeumann, 2, 13},
+		{GAMoore, 1, 9},
+		{GAMoore, 2, 25},
+	}
+	for _, tt := range tests {
+		ga.Neighbourhood, ga.Radius = tt.n, tt.radius
+		if n := ga.Neighbours(33); len(n) != tt.size {
+			t.Errorf("neighbourhood %d radius %d has %d cells; want %d", tt.n, tt.radius, len(n), tt.size)
+		}
+	}
+	// The corner cell wraps around to the opposite edges.
+	ga.Neighbourhood, ga.Radius = GAVonNeumann, 1
+	got := ga.Neighbours(0)
+	sort.Ints(got)
+	if want := []int{0, 1, 9, 10, 70}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbours(0) = %v; want %v", got, want)
+	}
+	// A grid smaller than the neighbourhood does not count cells twice.
+	ga = NewGACellular(GAParameter{}, 2, 2)
+	ga.Neighbourhood = GAMoore
+	if n := ga.Neighbours(0); len(n) != 4 {
+		t.Errorf("Neighbours(0) on a 2x2 grid = %v; want the 4 cells", n)
+	}
+}
+
+func TestCellular(t *testing.T) {
+	for _, update := range []GAUpdate{GASynchronous, GALineSweep, GAFixedRandomSweep, GANewRandomSweep, GAUniformChoice} {
+		ga := NewGACellular(GAParameter{
+			Initializer: new(GARandomInitializer),
+			Selector:    NewGATournamentSelector(0.7, 2),
+			Breeder:     new(GAUniformBreeder),
+			Mutator:     NewGAGaussianMutator(0.5, 0),
+			PMutate:     0.3,
+			PBreed:      0.9}, 8, 8)
+		ga.Update = update
+		ga.Neighbourhood = GAMoore
+		ga.Init(NewFloatGenome(make([]float64, 4), sphere, 10, -10))
+		last := ga.Best().Score()
+		for i := 0; i < 30; i++ {
+			ga.Optimize(1)
+			// A cell only takes a child at least as good.
+			if best := ga.Best().Score(); best > last {
+				t.Fatalf("update %d: best score went from %v to %v", update, last, best)
+			}
+			last = ga.Best().Score()
+		}
+		if last > 1 || ga.Generation() != 30 {
+			t.Errorf("update %d: best score %v after %d generations; want below 1 after 30", update, last, ga.Generation())
+		}
+		if g := ga.Cell(3, 5); g != ga.grid[43] {
+			t.Errorf("Cell(3, 5) is not cell 43")
+		}
+	}
+}
+
+// Evaluator scoring float genomes by sphere that fails after a number of
+// calls.
+type failingEvaluator struct{ calls, after int }
+
+func (e *failingEvaluator) Evaluate(pop GAGenomes) error {
+	if e.calls++; e.calls > e.after {
+		return errors.New("evaluator failed")
+	}
+	for _, g := range unscored(pop) {
+		g.(GAScoreSetter).SetScore(sphere(g.(*GAFloatGenome)))
+	}
+	return nil
+}
+
+func (e *failingEvaluator) String() string { return "failingEvaluator" }
+
+func TestCellularEvaluatorError(t *testing.T) {
+	for _, update := range []GAUpdate{GASynchronous, GALineSweep} {
+		ga := NewGACellular(GAParameter{
+			Initializer: new(GARandomInitializer),
+			Selector:    NewGATournamentSelector(0.7, 2),
+			Breeder:     new(GAUniformBreeder),
+			Mutator:     NewGAGaussianMutator(0.5, 0),
+			Evaluator:   &failingEvaluator{after: 20},
+			PMutate:     0.3,
+			PBreed:      0.9}, 4, 4)
+		ga.Update = update
+		ga.Init(NewFloatGenome(make([]float64, 4), nil, 10, -10))
+		ga.Optimize(30)
+		if ga.Err() == nil || ga.Generation() >= 30 {
+			t.Errorf("update %d: stopped at generation %d with %v; want an error before 30", update, ga.Generation(), ga.Err())
+		}
+		ga.Best()
+	}
+}
+
+// Tests that synchronous updates score the children of a generation in two
+// batches, the bred and the mutated ones, instead of one per cell.
+func TestCellularEvaluatorBatches(t *testing.T) {
+	e := &failingEvaluator{after: 1000}
+	ga := NewGACellular(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 2),
+		Breeder:     new(GAUniformBreeder),
+		Mutator:     NewGAGaussianMutator(0.5, 0),
+		Evaluator:   e,
+		PMutate:     0.3,
+		PBreed:      0.9}, 4, 4)
+	ga.Init(NewFloatGenome(make([]float64, 4), nil, 10, -10))
+	ga.Optimize(5)
+	if e.calls != 1+2*5 {
+		t.Errorf("Evaluator called %d times in Init and 5 generations; want %d", e.calls, 1+2*5)
+	}
+	if ga.Err() != nil {
+		t.Errorf("Err() = %v", ga.Err())
+	}
+}
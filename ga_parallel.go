@@ -3,7 +3,7 @@ Copyright 2010 Thomas Jager <mail@jager.no> All rights reserved.
 Use of this source code is governed by a BSD-style
 license that can be found in the LICENSE file.
 
-Crude Parallel Genetic Algorithm
+Parallel Genetic Algorithm, an island model with migration
 */
 
 package ga
@@ -12,23 +12,89 @@ import (
 	"fmt"
 )
 
+// Operators that change their own state while optimizing, like the usage
+// counts of a GAMultiMutator, implement GACloner so every island of a
+// GAParallel works on its own copy. Operators without such state are shared
+// by the islands.
+type GACloner interface {
+	// Clone returns a copy of the operator that shares no changing state
+	// with it.
+	Clone() interface{}
+}
+
+// clone returns a copy of p with every operator implementing GACloner
+// cloned.
+func (p GAParameter) clone() GAParameter {
+	if c, ok := p.Initializer.(GACloner); ok {
+		p.Initializer = c.Clone().(GAInitializer)
+	}
+	if c, ok := p.Selector.(GACloner); ok {
+		p.Selector = c.Clone().(GASelector)
+	}
+	if c, ok := p.Mutator.(GACloner); ok {
+		p.Mutator = c.Clone().(GAMutator)
+	}
+	if c, ok := p.Breeder.(GACloner); ok {
+		p.Breeder = c.Clone().(GABreeder)
+	}
+	if c, ok := p.Neural.(GACloner); ok {
+		p.Neural = c.Clone().(GANeural)
+	}
+	if c, ok := p.Replacement.(GACloner); ok {
+		p.Replacement = c.Clone().(GAReplacement)
+	}
+	if c, ok := p.Evaluator.(GACloner); ok {
+		p.Evaluator = c.Clone().(GAEvaluator)
+	}
+	return p
+}
+
 type GAParallel struct {
-	ga        []*GA
+	ga []*GA
+	// Parameters of the first island
 	Parameter GAParameter
 	numproc   int
+
+	// How genomes move between the islands, NewGAParallel sets it to send
+	// the best 2 genomes around a ring every generation
+	Migration GAMigration
 }
 
+// NewGAParallel returns a GAParallel with numproc islands using the same
+// parameters.
 func NewGAParallel(parameter GAParameter, numproc int) *GAParallel {
+	parameters := make([]GAParameter, numproc)
+	for i := range parameters {
+		parameters[i] = parameter
+	}
+	return NewGAParallelIslands(parameters...)
+}
+
+// NewGAParallelIslands returns a GAParallel with an island for each of the
+// parameters, so islands can use different operators and rates. Every island
+// gets its own clone of the operators implementing GACloner, use Island to
+// get at them.
+func NewGAParallelIslands(parameters ...GAParameter) *GAParallel {
+	if len(parameters) == 0 {
+		panic("No island parameters")
+	}
 	gap := new(GAParallel)
-	gap.Parameter = parameter
-	gap.ga = make([]*GA, numproc)
-	gap.numproc = numproc
-	for i := 0; i < numproc; i++ {
-		gap.ga[i] = NewGA(parameter)
+	gap.Parameter = parameters[0]
+	gap.ga = make([]*GA, len(parameters))
+	gap.numproc = len(parameters)
+	gap.Migration = GAMigration{Topology: new(GARingTopology), Interval: 1, Size: 2}
+	for i, p := range parameters {
+		gap.ga[i] = NewGA(p.clone())
 	}
 	return gap
 }
 
+// Island returns the GA of the i'th island.
+func (ga *GAParallel) Island(i int) *GA { return ga.ga[i] }
+
+// Islands returns the number of islands.
+func (ga *GAParallel) Islands() int { return ga.numproc }
+
 func (ga *GAParallel) String() string {
 	return fmt.Sprintf("Initializer = %s, Selector = %s, Mutator = %s Breeder = %s",
 		ga.Parameter.Initializer,
@@ -43,40 +109,88 @@ func (ga *GAParallel) Init(popsize int, init GAGenome) {
 	}
 }
 
+// InitIslands initializes every island from its own genome, so islands can
+// use different genome encodings. Migration.Convert must then turn emigrants
+// into the encoding of the island they move to.
+func (ga *GAParallel) InitIslands(popsize int, init ...GAGenome) {
+	if len(init) != ga.numproc {
+		panic("Need one genome for every island")
+	}
+	for i := 0; i < ga.numproc; i++ {
+		ga.ga[i].Init(popsize, init[i])
+	}
+}
+
 func optimize_worker(ga *GA, gen int, c chan int) {
 	ga.Optimize(gen)
 	c <- 1
 }
 
+// Optimize runs gen generations on every island, migrating every
+// Migration.Interval generations. It stops when the Evaluator of an island
+// fails, see Err.
 func (ga *GAParallel) Optimize(gen int) {
-	c := make(chan int, ga.numproc)
-	for i := 0; i < ga.numproc; i++ {
-		go optimize_worker(ga.ga[i], gen, c)
-	}
-	for i := 0; i < ga.numproc; i++ {
-		<-c
-	}
-	nselect := gen * 2
-	children := make([]GAGenomes, ga.numproc)
-	for i := 0; i < ga.numproc; i++ {
-		children[i] = make(GAGenomes, nselect)
-		for j := 0; j < nselect; j++ {
-			children[i][j] = ga.ga[i].Parameter.Selector.SelectOne(ga.ga[i].pop)
+	for gen > 0 && ga.Err() == nil {
+		step := gen
+		if m := ga.Migration.Interval; m > 0 {
+			if s := m - ga.ga[0].generation%m; s < step {
+				step = s
+			}
+		}
+		c := make(chan int, ga.numproc)
+		for i := 0; i < ga.numproc; i++ {
+			go optimize_worker(ga.ga[i], step, c)
+		}
+		for i := 0; i < ga.numproc; i++ {
+			<-c
+		}
+		gen -= step
+		if ga.Err() != nil {
+			return
+		}
+		if m := ga.Migration.Interval; m > 0 && ga.ga[0].generation%m == 0 {
+			ga.Migration.migrate(ga.ga)
 		}
 	}
-	j := ga.numproc - 1
+}
+
+// Err returns the first error of the Evaluator of an island, nil if there
+// was none.
+func (ga *GAParallel) Err() error {
 	for i := 0; i < ga.numproc; i++ {
-		ga.ga[i].pop = AppendGenomes(ga.ga[i].pop, children[j])
-		j--
+		if err := ga.ga[i].Err(); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 func (ga *GAParallel) OptimizeUntil(stop func(best GAGenome) bool) {
-	for !stop(ga.Best()) {
+	for ga.Err() == nil && !stop(ga.Best()) {
 		ga.Optimize(1)
 	}
 }
 
+// HallOfFame returns the best distinct genomes seen by any island since
+// Init, best first, as many as the largest Parameter.HallOfFame of the
+// islands.
+func (ga *GAParallel) HallOfFame() GAGenomes {
+	size := 0
+	for i := 0; i < ga.numproc; i++ {
+		if n := ga.ga[i].Parameter.HallOfFame; n > size {
+			size = n
+		}
+	}
+	if size <= 0 {
+		return nil
+	}
+	h := NewGAHallOfFame(size)
+	for i := 0; i < ga.numproc; i++ {
+		h.Update(ga.ga[i].HallOfFame())
+	}
+	return h.Genomes()
+}
+
 func (ga *GAParallel) Best() GAGenome {
 	best := ga.ga[0].Best()
 	for i := 1; i < ga.numproc; i++ {
@@ -0,0 +1,90 @@
+package ga
+
+import (
+	"testing"
+)
+
+func TestGAParallelIslandOperators(t *testing.T) {
+	m := NewMultiMutator()
+	m.Add(NewGAGaussianMutator(1, 0))
+	m.Add(new(GAPolynomialMutator))
+	param := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Mutator:     m,
+		PMutate:     1}
+	gap := NewGAParallel(param, 4)
+	gap.Init(10, NewFloatGenome(make([]float64, 3), sphere, 10, -10))
+	gap.Optimize(5)
+	seen := map[GAMutator]bool{m: true}
+	for i := 0; i < gap.Islands(); i++ {
+		im := gap.Island(i).Parameter.Mutator.(*GAMultiMutator)
+		if seen[im] {
+			t.Fatalf("island %d shares its GAMultiMutator", i)
+		}
+		seen[im] = true
+		if n := im.stats[0] + im.stats[1]; n != 50 {
+			t.Errorf("island %d GAMultiMutator used %d times; want 50", i, n)
+		}
+	}
+	if m.stats[0]+m.stats[1] != 0 {
+		t.Errorf("original GAMultiMutator used by the islands")
+	}
+}
+
+func TestGAParallelHeterogeneousIslands(t *testing.T) {
+	float32Sphere := func(g *GAFloat32Genome) float32 {
+		var s float32
+		for _, x := range g.Gene {
+			s += x * x
+		}
+		return s
+	}
+	gap := NewGAParallelIslands(
+		GAParameter{
+			Initializer: new(GARandomInitializer),
+			Selector:    NewGATournamentSelector(0.7, 5),
+			Mutator:     NewGAGaussianMutator(1, 0),
+			PMutate:     0.5},
+		GAParameter{
+			Initializer: new(GALatinHypercubeInitializer),
+			Selector:    NewGATournamentSelector(0.9, 3),
+			Breeder:     new(GA2PointBreeder),
+			Mutator:     &GACauchyMutator{Scale: 0.5},
+			PMutate:     0.2,
+			PBreed:      0.8})
+	gap.Migration.Convert = func(g GAGenome, from, to int) GAGenome {
+		switch g := g.(type) {
+		case *GAFloatGenome:
+			genes := make([]float32, len(g.Gene))
+			for i, x := range g.Gene {
+				genes[i] = float32(x)
+			}
+			return NewFloat32Genome(genes, float32Sphere, float32(g.Max), float32(g.Min))
+		case *GAFloat32Genome:
+			genes := make([]float64, len(g.Gene))
+			for i, x := range g.Gene {
+				genes[i] = float64(x)
+			}
+			return NewFloatGenome(genes, sphere, float64(g.Max), float64(g.Min))
+		}
+		return g
+	}
+	gap.InitIslands(10,
+		NewFloatGenome(make([]float64, 3), sphere, 10, -10),
+		NewFloat32Genome(make([]float32, 3), float32Sphere, 10, -10))
+	gap.Optimize(10)
+	for _, g := range gap.Island(0).pop {
+		if _, ok := g.(*GAFloatGenome); !ok {
+			t.Fatalf("island 0 holds %T; want *GAFloatGenome", g)
+		}
+	}
+	for _, g := range gap.Island(1).pop {
+		if _, ok := g.(*GAFloat32Genome); !ok {
+			t.Fatalf("island 1 holds %T; want *GAFloat32Genome", g)
+		}
+	}
+	if gap.Parameter.PMutate != 0.5 {
+		t.Errorf("Parameter.PMutate = %v; want the first island's 0.5", gap.Parameter.PMutate)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Per generation statistics of a GA run.
+*/
+
+package ga
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type GAStats struct {
+	Generation int
+	// Scores of the population after the generation.
+	Best, Worst, Mean, StdDev float64
+	// Diversity of the population, if measured.
+	Diversity float64
+	// Number of genomes reseeded after the generation by GARestart.
+	Restarted int
+	// Values of the scheduled parameters during the generation.
+	Parameters map[string]float64
+}
+
+func (s GAStats) String() string {
+	o := []string{fmt.Sprintf("Generation %d: Best = %f Worst = %f Mean = %f StdDev = %f",
+		s.Generation, s.Best, s.Worst, s.Mean, s.StdDev)}
+	names := make([]string, 0, len(s.Parameters))
+	for name := range s.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if s.Restarted > 0 {
+		o = append(o, fmt.Sprintf("Diversity = %f Restarted = %d", s.Diversity, s.Restarted))
+	} else if s.Diversity != 0 {
+		o = append(o, fmt.Sprintf("Diversity = %f", s.Diversity))
+	}
+	for _, name := range names {
+		o = append(o, fmt.Sprintf("%s = %f", name, s.Parameters[name]))
+	}
+	return strings.Join(o, " ")
+}
+
+// record appends the statistics of the current, sorted, population.
+func (ga *GA) record() {
+	if len(ga.pop) == 0 {
+		return
+	}
+	var sk Sketch
+	for _, g := range ga.pop {
+		sk.Add(g.Score())
+	}
+	s := GAStats{
+		Generation: ga.generation,
+		Best:       ga.pop[0].Score(),
+		Worst:      ga.pop[len(ga.pop)-1].Score(),
+		Mean:       sk.Average(),
+		StdDev:     math.Sqrt(math.Max(0, sk.Variance())),
+	}
+	if d := ga.diversity(); d != nil {
+		s.Diversity = d(ga.pop)
+	}
+	if len(ga.schedules) > 0 {
+		s.Parameters = make(map[string]float64, len(ga.schedules))
+		for _, sc := range ga.schedules {
+			s.Parameters[sc.name] = *sc.target
+		}
+	}
+	ga.history = append(ga.history, s)
+}
+
+// diversity returns the diversity measure to record, nil if none.
+func (ga *GA) diversity() func(GAGenomes) float64 {
+	if ga.Parameter.Diversity != nil {
+		return ga.Parameter.Diversity
+	}
+	if ga.Parameter.Restart != nil {
+		return Diversity
+	}
+	return nil
+}
+
+// Stats returns the statistics of the last generation.
+func (ga *GA) Stats() GAStats {
+	if len(ga.history) == 0 {
+		return GAStats{}
+	}
+	return ga.history[len(ga.history)-1]
+}
+
+// History returns the statistics of every generation since Init.
+func (ga *GA) History() []GAStats { return ga.history }
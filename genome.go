@@ -33,6 +33,41 @@ type GAGenome interface {
 	Len() int
 }
 
+// Optional interface for genomes that can randomize a single gene without
+// randomizing a whole copy of the genome. Operators changing a few genes use
+// it through RandomizeGene. Initializers and GARandomBreeder randomize every
+// gene of a copy they make anyway, so they use Randomize.
+type GAGeneRandomizer interface {
+	//Randomize gene i
+	RandomizeGene(i int)
+}
+
+// RandomizeGene randomizes gene i of g. It uses g.RandomizeGene if g implements
+// GAGeneRandomizer, otherwise it randomizes a copy of g and splices gene i
+// from it.
+func RandomizeGene(g GAGenome, i int) {
+	if r, ok := g.(GAGeneRandomizer); ok {
+		r.RandomizeGene(i)
+		return
+	}
+	randomizeGeneSplice(g, i)
+}
+
+func randomizeGeneSplice(g GAGenome, i int) {
+	r := g.Copy()
+	r.Randomize()
+	g.Splice(r, i, i, 1)
+}
+
+// Optional interface for genomes whose score can be computed elsewhere, for
+// example by a GAEvaluator, and cached in the genome.
+type GAScoreSetter interface {
+	//Whether the score is cached
+	HasScore() bool
+	//Cache score as the score of the genome
+	SetScore(score float64)
+}
+
 type GAGenomes []GAGenome
 
 func (g GAGenomes) Len() int           { return len(g) }
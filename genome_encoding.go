@@ -0,0 +1,313 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+JSON and gob encoding of the built-in genomes. The score function is saved
+by the name it was registered under with RegisterScore and is looked up
+again when decoding. Genomes with a score function that is not registered
+are encoded without one.
+*/
+
+package ga
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Everything about a built-in genome that can be saved.
+type gaGenomeRecord struct {
+	Type     string
+	Score    string
+	Bools    []bool
+	Ints     []int
+	Float64s []float64
+	Float32s []float32
+	Sigma64  []float64
+	Sigma32  []float32
+	Min, Max float64
+	IntMin   int
+	IntMax   int
+	Cached   float64
+	HasScore bool
+}
+
+// encodeGenome returns the record of g. A score function that is not
+// registered is an error if strict is set and left out otherwise.
+func encodeGenome(g GAGenome, strict bool) (r gaGenomeRecord, err error) {
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		r = gaGenomeRecord{Type: "bitstring", Bools: g.Gene, Cached: g.score, HasScore: g.hasscore}
+		r.Score, err = scoreName(g.sfunc, strict)
+	case *GAIntGenome:
+		r = gaGenomeRecord{Type: "int", Ints: g.Gene, IntMin: g.min, IntMax: g.max, Cached: g.score, HasScore: g.hasscore}
+		r.Score, err = scoreName(g.sfunc, strict)
+	case *GAOrderedIntGenome:
+		r = gaGenomeRecord{Type: "orderedint", Ints: g.Gene, Cached: g.score, HasScore: g.hasscore}
+		r.Score, err = scoreName(g.sfunc, strict)
+	case *GAFloatGenome:
+		r = gaGenomeRecord{Type: "float", Float64s: g.Gene, Sigma64: g.Sigma, Min: g.Min, Max: g.Max, Cached: g.score, HasScore: g.hasscore}
+		r.Score, err = scoreName(g.sfunc, strict)
+	case *GAFloat32Genome:
+		r = gaGenomeRecord{Type: "float32", Float32s: g.Gene, Sigma32: g.Sigma, Min: float64(g.Min), Max: float64(g.Max), Cached: float64(g.score), HasScore: g.hasscore}
+		r.Score, err = scoreName(g.sfunc, strict)
+	default:
+		err = fmt.Errorf("ga: can not save genome of type %T", g)
+	}
+	return
+}
+
+func decodeGenome(r gaGenomeRecord) (GAGenome, error) {
+	switch r.Type {
+	case "bitstring":
+		g := &GAFixedBitstringGenome{Gene: r.Bools, score: r.Cached, hasscore: r.HasScore}
+		return g, scoreFunc(r.Score, &g.sfunc)
+	case "int":
+		g := &GAIntGenome{Gene: r.Ints, min: r.IntMin, max: r.IntMax, score: r.Cached, hasscore: r.HasScore}
+		return g, scoreFunc(r.Score, &g.sfunc)
+	case "orderedint":
+		g := &GAOrderedIntGenome{Gene: r.Ints, score: r.Cached, hasscore: r.HasScore}
+		return g, scoreFunc(r.Score, &g.sfunc)
+	case "float":
+		g := &GAFloatGenome{Gene: r.Float64s, Sigma: r.Sigma64, Min: r.Min, Max: r.Max, score: r.Cached, hasscore: r.HasScore}
+		return g, scoreFunc(r.Score, &g.sfunc)
+	case "float32":
+		g := &GAFloat32Genome{Gene: r.Float32s, Sigma: r.Sigma32, Min: float32(r.Min), Max: float32(r.Max), score: float32(r.Cached), hasscore: r.HasScore}
+		return g, scoreFunc(r.Score, &g.sfunc)
+	}
+	return nil, fmt.Errorf("ga: unknown genome type %q", r.Type)
+}
+
+func encodeGenomes(pop GAGenomes, strict bool) ([]gaGenomeRecord, error) {
+	r := make([]gaGenomeRecord, len(pop))
+	for i, g := range pop {
+		var err error
+		if r[i], err = encodeGenome(g, strict); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func decodeGenomes(r []gaGenomeRecord) (GAGenomes, error) {
+	pop := make(GAGenomes, len(r))
+	for i := range r {
+		var err error
+		if pop[i], err = decodeGenome(r[i]); err != nil {
+			return nil, err
+		}
+	}
+	return pop, nil
+}
+
+// JSON form of the built-in genomes. Gene, Min, Max and Sigma hold the
+// fields of the genome type, Score the cached score if there is one.
+type gaGenomeJSON struct {
+	Gene      interface{} `json:"gene"`
+	Min       interface{} `json:"min,omitempty"`
+	Max       interface{} `json:"max,omitempty"`
+	Sigma     interface{} `json:"sigma,omitempty"`
+	Score     *float64    `json:"score,omitempty"`
+	ScoreFunc string      `json:"scorefunc,omitempty"`
+}
+
+func cachedScore(score float64, hasscore bool) *float64 {
+	if !hasscore {
+		return nil
+	}
+	return &score
+}
+
+func gobEncodeGenome(g GAGenome) ([]byte, error) {
+	r, err := encodeGenome(g, false)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	err = gob.NewEncoder(&b).Encode(r)
+	return b.Bytes(), err
+}
+
+func gobDecodeGenome(data []byte, want string) (GAGenome, error) {
+	var r gaGenomeRecord
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&r); err != nil {
+		return nil, err
+	}
+	if r.Type != want {
+		return nil, fmt.Errorf("ga: can not decode a %s genome into a %s genome", r.Type, want)
+	}
+	return decodeGenome(r)
+}
+
+func (g *GAFixedBitstringGenome) MarshalJSON() ([]byte, error) {
+	name, _ := scoreName(g.sfunc, false)
+	return json.Marshal(gaGenomeJSON{Gene: g.Gene, Score: cachedScore(g.score, g.hasscore), ScoreFunc: name})
+}
+
+func (g *GAFixedBitstringGenome) UnmarshalJSON(data []byte) error {
+	var n GAFixedBitstringGenome
+	j := gaGenomeJSON{Gene: &n.Gene}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Score != nil {
+		n.score, n.hasscore = *j.Score, true
+	}
+	if err := scoreFunc(j.ScoreFunc, &n.sfunc); err != nil {
+		return err
+	}
+	*g = n
+	return nil
+}
+
+func (g *GAFixedBitstringGenome) GobEncode() ([]byte, error) { return gobEncodeGenome(g) }
+
+func (g *GAFixedBitstringGenome) GobDecode(data []byte) error {
+	d, err := gobDecodeGenome(data, "bitstring")
+	if err != nil {
+		return err
+	}
+	*g = *d.(*GAFixedBitstringGenome)
+	return nil
+}
+
+func (g *GAIntGenome) MarshalJSON() ([]byte, error) {
+	name, _ := scoreName(g.sfunc, false)
+	return json.Marshal(gaGenomeJSON{Gene: g.Gene, Min: g.min, Max: g.max,
+		Score: cachedScore(g.score, g.hasscore), ScoreFunc: name})
+}
+
+func (g *GAIntGenome) UnmarshalJSON(data []byte) error {
+	var n GAIntGenome
+	j := gaGenomeJSON{Gene: &n.Gene, Min: &n.min, Max: &n.max}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Score != nil {
+		n.score, n.hasscore = *j.Score, true
+	}
+	if err := scoreFunc(j.ScoreFunc, &n.sfunc); err != nil {
+		return err
+	}
+	*g = n
+	return nil
+}
+
+func (g *GAIntGenome) GobEncode() ([]byte, error) { return gobEncodeGenome(g) }
+
+func (g *GAIntGenome) GobDecode(data []byte) error {
+	d, err := gobDecodeGenome(data, "int")
+	if err != nil {
+		return err
+	}
+	*g = *d.(*GAIntGenome)
+	return nil
+}
+
+func (g *GAOrderedIntGenome) MarshalJSON() ([]byte, error) {
+	name, _ := scoreName(g.sfunc, false)
+	return json.Marshal(gaGenomeJSON{Gene: g.Gene, Score: cachedScore(g.score, g.hasscore), ScoreFunc: name})
+}
+
+func (g *GAOrderedIntGenome) UnmarshalJSON(data []byte) error {
+	var n GAOrderedIntGenome
+	j := gaGenomeJSON{Gene: &n.Gene}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Score != nil {
+		n.score, n.hasscore = *j.Score, true
+	}
+	if err := scoreFunc(j.ScoreFunc, &n.sfunc); err != nil {
+		return err
+	}
+	*g = n
+	return nil
+}
+
+func (g *GAOrderedIntGenome) GobEncode() ([]byte, error) { return gobEncodeGenome(g) }
+
+func (g *GAOrderedIntGenome) GobDecode(data []byte) error {
+	d, err := gobDecodeGenome(data, "orderedint")
+	if err != nil {
+		return err
+	}
+	*g = *d.(*GAOrderedIntGenome)
+	return nil
+}
+
+func (g *GAFloatGenome) MarshalJSON() ([]byte, error) {
+	name, _ := scoreName(g.sfunc, false)
+	j := gaGenomeJSON{Gene: g.Gene, Min: g.Min, Max: g.Max,
+		Score: cachedScore(g.score, g.hasscore), ScoreFunc: name}
+	if len(g.Sigma) > 0 {
+		j.Sigma = g.Sigma
+	}
+	return json.Marshal(j)
+}
+
+func (g *GAFloatGenome) UnmarshalJSON(data []byte) error {
+	var n GAFloatGenome
+	j := gaGenomeJSON{Gene: &n.Gene, Min: &n.Min, Max: &n.Max, Sigma: &n.Sigma}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Score != nil {
+		n.score, n.hasscore = *j.Score, true
+	}
+	if err := scoreFunc(j.ScoreFunc, &n.sfunc); err != nil {
+		return err
+	}
+	*g = n
+	return nil
+}
+
+func (g *GAFloatGenome) GobEncode() ([]byte, error) { return gobEncodeGenome(g) }
+
+func (g *GAFloatGenome) GobDecode(data []byte) error {
+	d, err := gobDecodeGenome(data, "float")
+	if err != nil {
+		return err
+	}
+	*g = *d.(*GAFloatGenome)
+	return nil
+}
+
+func (g *GAFloat32Genome) MarshalJSON() ([]byte, error) {
+	name, _ := scoreName(g.sfunc, false)
+	j := gaGenomeJSON{Gene: g.Gene, Min: g.Min, Max: g.Max,
+		Score: cachedScore(float64(g.score), g.hasscore), ScoreFunc: name}
+	if len(g.Sigma) > 0 {
+		j.Sigma = g.Sigma
+	}
+	return json.Marshal(j)
+}
+
+func (g *GAFloat32Genome) UnmarshalJSON(data []byte) error {
+	var n GAFloat32Genome
+	j := gaGenomeJSON{Gene: &n.Gene, Min: &n.Min, Max: &n.Max, Sigma: &n.Sigma}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Score != nil {
+		n.score, n.hasscore = float32(*j.Score), true
+	}
+	if err := scoreFunc(j.ScoreFunc, &n.sfunc); err != nil {
+		return err
+	}
+	*g = n
+	return nil
+}
+
+func (g *GAFloat32Genome) GobEncode() ([]byte, error) { return gobEncodeGenome(g) }
+
+func (g *GAFloat32Genome) GobDecode(data []byte) error {
+	d, err := gobDecodeGenome(data, "float32")
+	if err != nil {
+		return err
+	}
+	*g = *d.(*GAFloat32Genome)
+	return nil
+}
@@ -0,0 +1,95 @@
+package ga
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterScore("countTrue", bitsScore)
+	RegisterScore("firstGene", firstGene)
+	RegisterScore("orderedFirst", orderedFirst)
+	RegisterScore("float32First", float32First)
+}
+
+func bitsScore(g *GAFixedBitstringGenome) float64 { return float64(countTrue(g.Gene)) }
+func orderedFirst(g *GAOrderedIntGenome) float64  { return float64(g.Gene[0]) }
+func float32First(g *GAFloat32Genome) float32     { return g.Gene[0] }
+
+func encodingGenomes() []GAGenome {
+	f := NewFloatGenome([]float64{1.5, -2}, sphere, 10, -10)
+	f.Sigma = []float64{0.1, 0.2}
+	f.Score()
+	return []GAGenome{
+		NewFixedBitstringGenome([]bool{true, false, true}, bitsScore),
+		NewIntGenome([]int{3, -1}, firstGene, -5, 5),
+		NewOrderedIntGenome([]int{2, 0, 1}, orderedFirst),
+		f,
+		NewFloat32Genome([]float32{0.25, 4}, float32First, 8, 0),
+	}
+}
+
+// Tests that genomes survive a JSON and a gob round trip, score function
+// included.
+func TestGenomeEncoding(t *testing.T) {
+	for _, g := range encodingGenomes() {
+		want := g.Score()
+		for _, codec := range []string{"json", "gob"} {
+			d := reflect.New(reflect.TypeOf(g).Elem()).Interface().(GAGenome)
+			var err error
+			if codec == "json" {
+				var data []byte
+				if data, err = json.Marshal(g); err == nil {
+					err = json.Unmarshal(data, d)
+				}
+			} else {
+				var b bytes.Buffer
+				if err = gob.NewEncoder(&b).Encode(g); err == nil {
+					err = gob.NewDecoder(&b).Decode(d)
+				}
+			}
+			if err != nil {
+				t.Errorf("%s round trip of %T: %v", codec, g, err)
+				continue
+			}
+			if !reflect.DeepEqual(d.String(), g.String()) {
+				t.Errorf("%s round trip of %v = %v", codec, g, d)
+			}
+			d.Reset()
+			if got := d.Score(); got != want {
+				t.Errorf("%s round trip of %T: Score() = %v; want %v", codec, g, got, want)
+			}
+		}
+	}
+	var d GAIntGenome
+	data, _ := json.Marshal(NewIntGenome([]int{1}, firstGene, -5, 5))
+	if err := json.Unmarshal(data, &d); err != nil || d.Min() != -5 || d.Max() != 5 {
+		t.Errorf("JSON round trip of GAIntGenome range = [%d,%d], %v; want [-5,5]", d.Min(), d.Max(), err)
+	}
+}
+
+func TestGenomeJSONFormat(t *testing.T) {
+	g := NewFloatGenome([]float64{1, 2}, sphere, 3, 0)
+	g.Score()
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"gene":[1,2],"min":0,"max":3,"score":5,"scorefunc":"sphere"}`; string(data) != want {
+		t.Errorf("json.Marshal = %s; want %s", data, want)
+	}
+	var d GAOrderedIntGenome
+	err = json.Unmarshal([]byte(`{"gene":[0,1],"scorefunc":"nope"}`), &d)
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("json.Unmarshal with unknown score function = %v; want error", err)
+	}
+	var b bytes.Buffer
+	gob.NewEncoder(&b).Encode(g)
+	if err := gob.NewDecoder(&b).Decode(&d); err == nil {
+		t.Errorf("gob decoding a float genome into an ordered genome = nil; want error")
+	}
+}
@@ -10,7 +10,6 @@ package ga
 
 import (
 	"fmt"
-	"math/rand"
 )
 
 type GAFixedBitstringGenome struct {
@@ -56,7 +55,7 @@ func (g *GAFixedBitstringGenome) Switch(x, y int) {
 func (g *GAFixedBitstringGenome) Randomize() {
 	l := len(g.Gene)
 	for i := 0; i < l; i++ {
-		x := rand.Intn(2)
+		x := rng.Intn(2)
 		if x == 1 {
 			g.Gene[i] = true
 		} else {
@@ -66,6 +65,11 @@ func (g *GAFixedBitstringGenome) Randomize() {
 	g.Reset()
 }
 
+func (g *GAFixedBitstringGenome) RandomizeGene(i int) {
+	g.Gene[i] = rng.Intn(2) == 1
+	g.Reset()
+}
+
 func (g *GAFixedBitstringGenome) Copy() GAGenome {
 	n := new(GAFixedBitstringGenome)
 	n.Gene = make([]bool, len(g.Gene))
@@ -88,6 +92,10 @@ func (g *GAFixedBitstringGenome) Score() float64 {
 
 func (g *GAFixedBitstringGenome) Reset() { g.hasscore = false }
 
+func (g *GAFixedBitstringGenome) HasScore() bool { return g.hasscore }
+
+func (g *GAFixedBitstringGenome) SetScore(score float64) { g.score, g.hasscore = score, true }
+
 func (g *GAFixedBitstringGenome) String() string {
 	return fmt.Sprintf("%v", g.Gene)
 }
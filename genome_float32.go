@@ -11,14 +11,16 @@ package ga
 
 import (
 	"fmt"
-	"math/rand"
 )
 
 type GAFloat32Genome struct {
-	Gene     []float32
-	score    float32
-	Max      float32
-	Min      float32
+	Gene  []float32
+	score float32
+	Max   float32
+	Min   float32
+	// Mutation step sizes used by GASelfAdaptiveMutator, either one for
+	// the whole genome or one per gene.
+	Sigma    []float32
 	hasscore bool
 	sfunc    func(ga *GAFloat32Genome) float32
 }
@@ -39,6 +41,10 @@ func (a *GAFloat32Genome) Crossover(bi GAGenome, p1, p2 int) (GAGenome, GAGenome
 	cb := b.Copy().(*GAFloat32Genome)
 	copy(ca.Gene[p1:p2+1], b.Gene[p1:p2+1])
 	copy(cb.Gene[p1:p2+1], a.Gene[p1:p2+1])
+	if len(a.Sigma) == len(a.Gene) && len(b.Sigma) == len(b.Gene) {
+		copy(ca.Sigma[p1:p2+1], b.Sigma[p1:p2+1])
+		copy(cb.Sigma[p1:p2+1], a.Sigma[p1:p2+1])
+	}
 	ca.Reset()
 	cb.Reset()
 	return ca, cb
@@ -47,6 +53,9 @@ func (a *GAFloat32Genome) Crossover(bi GAGenome, p1, p2 int) (GAGenome, GAGenome
 func (a *GAFloat32Genome) Splice(bi GAGenome, from, to, length int) {
 	b := bi.(*GAFloat32Genome)
 	copy(a.Gene[to:length+to], b.Gene[from:length+from])
+	if len(a.Sigma) == len(a.Gene) && len(b.Sigma) == len(b.Gene) {
+		copy(a.Sigma[to:length+to], b.Sigma[from:length+from])
+	}
 	a.Reset()
 }
 
@@ -57,21 +66,33 @@ func (g *GAFloat32Genome) Valid() bool {
 
 func (g *GAFloat32Genome) Switch(x, y int) {
 	g.Gene[x], g.Gene[y] = g.Gene[y], g.Gene[x]
+	if len(g.Sigma) == len(g.Gene) {
+		g.Sigma[x], g.Sigma[y] = g.Sigma[y], g.Sigma[x]
+	}
 	g.Reset()
 }
 
 func (g *GAFloat32Genome) Randomize() {
 	l := len(g.Gene)
 	for i := 0; i < l; i++ {
-		g.Gene[i] = rand.Float32()*(g.Max-g.Min) + g.Min
+		g.Gene[i] = rng.Float32()*(g.Max-g.Min) + g.Min
 	}
 	g.Reset()
 }
 
+func (g *GAFloat32Genome) RandomizeGene(i int) {
+	g.Gene[i] = rng.Float32()*(g.Max-g.Min) + g.Min
+	g.Reset()
+}
+
 func (g *GAFloat32Genome) Copy() GAGenome {
 	n := new(GAFloat32Genome)
 	n.Gene = make([]float32, len(g.Gene))
 	copy(n.Gene, g.Gene)
+	if g.Sigma != nil {
+		n.Sigma = make([]float32, len(g.Sigma))
+		copy(n.Sigma, g.Sigma)
+	}
 	n.sfunc = g.sfunc
 	n.score = g.score
 	n.Max = g.Max
@@ -92,4 +113,8 @@ func (g *GAFloat32Genome) Score() float64 {
 
 func (g *GAFloat32Genome) Reset() { g.hasscore = false }
 
+func (g *GAFloat32Genome) HasScore() bool { return g.hasscore }
+
+func (g *GAFloat32Genome) SetScore(score float64) { g.score, g.hasscore = float32(score), true }
+
 func (g *GAFloat32Genome) String() string { return fmt.Sprintf("%v", g.Gene) }
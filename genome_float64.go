@@ -11,14 +11,16 @@ package ga
 
 import (
 	"fmt"
-	"math/rand"
 )
 
 type GAFloatGenome struct {
-	Gene     []float64
-	score    float64
-	Max      float64
-	Min      float64
+	Gene  []float64
+	score float64
+	Max   float64
+	Min   float64
+	// Mutation step sizes used by GASelfAdaptiveMutator, either one for
+	// the whole genome or one per gene.
+	Sigma    []float64
 	hasscore bool
 	sfunc    func(ga *GAFloatGenome) float64
 }
@@ -39,6 +41,10 @@ func (a *GAFloatGenome) Crossover(bi GAGenome, p1, p2 int) (GAGenome, GAGenome)
 	cb := b.Copy().(*GAFloatGenome)
 	copy(ca.Gene[p1:p2+1], b.Gene[p1:p2+1])
 	copy(cb.Gene[p1:p2+1], a.Gene[p1:p2+1])
+	if len(a.Sigma) == len(a.Gene) && len(b.Sigma) == len(b.Gene) {
+		copy(ca.Sigma[p1:p2+1], b.Sigma[p1:p2+1])
+		copy(cb.Sigma[p1:p2+1], a.Sigma[p1:p2+1])
+	}
 	ca.Reset()
 	cb.Reset()
 	return ca, cb
@@ -47,6 +53,9 @@ func (a *GAFloatGenome) Crossover(bi GAGenome, p1, p2 int) (GAGenome, GAGenome)
 func (a *GAFloatGenome) Splice(bi GAGenome, from, to, length int) {
 	b := bi.(*GAFloatGenome)
 	copy(a.Gene[to:length+to], b.Gene[from:length+from])
+	if len(a.Sigma) == len(a.Gene) && len(b.Sigma) == len(b.Gene) {
+		copy(a.Sigma[to:length+to], b.Sigma[from:length+from])
+	}
 	a.Reset()
 }
 
@@ -57,21 +66,33 @@ func (g *GAFloatGenome) Valid() bool {
 
 func (g *GAFloatGenome) Switch(x, y int) {
 	g.Gene[x], g.Gene[y] = g.Gene[y], g.Gene[x]
+	if len(g.Sigma) == len(g.Gene) {
+		g.Sigma[x], g.Sigma[y] = g.Sigma[y], g.Sigma[x]
+	}
 	g.Reset()
 }
 
 func (g *GAFloatGenome) Randomize() {
 	l := len(g.Gene)
 	for i := 0; i < l; i++ {
-		g.Gene[i] = rand.Float64()*(g.Max-g.Min) + g.Min
+		g.Gene[i] = rng.Float64()*(g.Max-g.Min) + g.Min
 	}
 	g.Reset()
 }
 
+func (g *GAFloatGenome) RandomizeGene(i int) {
+	g.Gene[i] = rng.Float64()*(g.Max-g.Min) + g.Min
+	g.Reset()
+}
+
 func (g *GAFloatGenome) Copy() GAGenome {
 	n := new(GAFloatGenome)
 	n.Gene = make([]float64, len(g.Gene))
 	copy(n.Gene, g.Gene)
+	if g.Sigma != nil {
+		n.Sigma = make([]float64, len(g.Sigma))
+		copy(n.Sigma, g.Sigma)
+	}
 	n.sfunc = g.sfunc
 	n.score = g.score
 	n.Max = g.Max
@@ -92,4 +113,8 @@ func (g *GAFloatGenome) Score() float64 {
 
 func (g *GAFloatGenome) Reset() { g.hasscore = false }
 
+func (g *GAFloatGenome) HasScore() bool { return g.hasscore }
+
+func (g *GAFloatGenome) SetScore(score float64) { g.score, g.hasscore = score, true }
+
 func (g *GAFloatGenome) String() string { return fmt.Sprintf("%v", g.Gene) }
@@ -8,7 +8,6 @@ package ga
 
 import (
 	"fmt"
-	"math/rand"
 )
 
 type GAIntGenome struct {
@@ -58,11 +57,16 @@ func (g *GAIntGenome) Randomize() {
 	min, max := g.min, g.max
 	r := max - min + 1
 	for i := range g.Gene {
-		g.Gene[i] = rand.Intn(r) + min
+		g.Gene[i] = rng.Intn(r) + min
 	}
 	g.Reset()
 }
 
+func (g *GAIntGenome) RandomizeGene(i int) {
+	g.Gene[i] = rng.Intn(g.max-g.min+1) + g.min
+	g.Reset()
+}
+
 func (g *GAIntGenome) Copy() GAGenome {
 	n := new(GAIntGenome)
 	n.Gene = make([]int, len(g.Gene))
@@ -77,6 +81,23 @@ func (g *GAIntGenome) Copy() GAGenome {
 
 func (g *GAIntGenome) Len() int { return len(g.Gene) }
 
+// Min returns the smallest value a gene can take.
+func (g *GAIntGenome) Min() int { return g.min }
+
+// Max returns the largest value a gene can take.
+func (g *GAIntGenome) Max() int { return g.max }
+
+// clamp limits v to [min,max].
+func (g *GAIntGenome) clamp(v int) int {
+	if v < g.min {
+		return g.min
+	}
+	if v > g.max {
+		return g.max
+	}
+	return v
+}
+
 func (g *GAIntGenome) Score() float64 {
 	if !g.hasscore {
 		g.score = g.sfunc(g)
@@ -87,4 +108,8 @@ func (g *GAIntGenome) Score() float64 {
 
 func (g *GAIntGenome) Reset() { g.hasscore = false }
 
+func (g *GAIntGenome) HasScore() bool { return g.hasscore }
+
+func (g *GAIntGenome) SetScore(score float64) { g.score, g.hasscore = score, true }
+
 func (g *GAIntGenome) String() string { return fmt.Sprintf("%v", g.Gene) }
@@ -12,7 +12,6 @@ import (
 	//"container/vector"
 	//"container/list"
 	"fmt"
-	"math/rand"
 	"sort"
 )
 
@@ -115,13 +114,21 @@ func (g *GAOrderedIntGenome) Switch(x, y int) {
 func (g *GAOrderedIntGenome) Randomize() {
 	l := len(g.Gene)
 	for i := 0; i < l; i++ {
-		x := rand.Intn(l)
-		y := rand.Intn(l)
+		x := rng.Intn(l)
+		y := rng.Intn(l)
 		g.Gene[x], g.Gene[y] = g.Gene[y], g.Gene[x]
 	}
 	g.Reset()
 }
 
+// RandomizeGene switches gene i with a random gene so the genome stays a
+// valid permutation.
+func (g *GAOrderedIntGenome) RandomizeGene(i int) {
+	j := rng.Intn(len(g.Gene))
+	g.Gene[i], g.Gene[j] = g.Gene[j], g.Gene[i]
+	g.Reset()
+}
+
 func (g *GAOrderedIntGenome) Copy() GAGenome {
 	n := new(GAOrderedIntGenome)
 	n.Gene = make([]int, len(g.Gene))
@@ -144,4 +151,8 @@ func (g *GAOrderedIntGenome) Score() float64 {
 
 func (g *GAOrderedIntGenome) Reset() { g.hasscore = false }
 
+func (g *GAOrderedIntGenome) HasScore() bool { return g.hasscore }
+
+func (g *GAOrderedIntGenome) SetScore(score float64) { g.score, g.hasscore = score, true }
+
 func (g *GAOrderedIntGenome) String() string { return fmt.Sprintf("%v", g.Gene) }
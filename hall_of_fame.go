@@ -0,0 +1,58 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Archive of the best distinct genomes seen during a run.
+*/
+
+package ga
+
+import (
+	"sort"
+)
+
+type GAHallOfFame struct {
+	size    int
+	genomes GAGenomes
+	seen    map[string]bool
+}
+
+// NewGAHallOfFame returns an empty hall of fame holding at most size genomes.
+func NewGAHallOfFame(size int) *GAHallOfFame {
+	return &GAHallOfFame{size: size, seen: make(map[string]bool)}
+}
+
+// Update adds copies of the genomes of pop that are better than the worst
+// genome in the hall of fame. Genomes are considered equal when their String()
+// is equal, so the hall of fame never holds the same genome twice.
+func (h *GAHallOfFame) Update(pop GAGenomes) {
+	if h.size <= 0 {
+		return
+	}
+	for _, g := range pop {
+		score := g.Score()
+		if len(h.genomes) == h.size && score >= h.genomes[h.size-1].Score() {
+			continue
+		}
+		key := g.String()
+		if h.seen[key] {
+			continue
+		}
+		if len(h.genomes) == h.size {
+			delete(h.seen, h.genomes[h.size-1].String())
+			h.genomes = h.genomes[:h.size-1]
+		}
+		h.seen[key] = true
+		// Insert keeping the genomes sorted, best first.
+		i := sort.Search(len(h.genomes), func(i int) bool { return h.genomes[i].Score() > score })
+		h.genomes = append(h.genomes, nil)
+		copy(h.genomes[i+1:], h.genomes[i:])
+		h.genomes[i] = g.Copy()
+	}
+}
+
+// Genomes returns a copy of the list of genomes in the hall of fame, best
+// first.
+func (h *GAHallOfFame) Genomes() GAGenomes { return append(GAGenomes(nil), h.genomes...) }
+
+func (h *GAHallOfFame) Len() int { return len(h.genomes) }
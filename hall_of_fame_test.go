@@ -0,0 +1,83 @@
+package ga
+
+import (
+	"testing"
+)
+
+func firstGene(g *GAIntGenome) float64 { return float64(g.Gene[0]) }
+
+func intGenomes(values ...int) GAGenomes {
+	pop := make(GAGenomes, len(values))
+	for i, v := range values {
+		pop[i] = NewIntGenome([]int{v}, firstGene, 0, 100)
+	}
+	return pop
+}
+
+func TestHallOfFameUpdate(t *testing.T) {
+	h := NewGAHallOfFame(3)
+	h.Update(intGenomes(5, 9, 5, 7))
+	h.Update(intGenomes(8, 1, 7))
+	var got []int
+	for _, g := range h.Genomes() {
+		got = append(got, g.(*GAIntGenome).Gene[0])
+	}
+	if len(got) != 3 || got[0] != 1 || got[1] != 5 || got[2] != 7 {
+		t.Errorf("HallOfFame = %v; want [1 5 7]", got)
+	}
+	// Changing the list returned leaves the hall of fame alone.
+	h.Genomes()[0] = intGenomes(50)[0]
+	if g := h.Genomes()[0].(*GAIntGenome).Gene[0]; g != 1 {
+		t.Errorf("best of the HallOfFame = %d after changing Genomes(); want 1", g)
+	}
+}
+
+// Tests that keepElite restores elite genomes a replacement step dropped.
+func TestKeepElite(t *testing.T) {
+	ga := NewGA(GAParameter{Elite: 2})
+	ga.pop = intGenomes(1, 2, 3, 4)
+	elite := ga.elite()
+	ga.pop = append(GAGenomes{ga.pop[1], ga.pop[3]}, intGenomes(5, 6)...)
+	ga.keepElite(elite)
+	var got []int
+	for _, g := range ga.pop {
+		got = append(got, g.(*GAIntGenome).Gene[0])
+	}
+	if got[0] != 1 || got[1] != 2 || got[2] != 4 || got[3] != 5 {
+		t.Errorf("population after keepElite = %v; want [1 2 4 5]", got)
+	}
+}
+
+func TestGAHallOfFame(t *testing.T) {
+	param := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		PMutate:     0.5,
+		PBreed:      0.5,
+		Elite:       2,
+		HallOfFame:  5}
+	ga := NewGA(param)
+	ga.Init(20, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	ga.Optimize(20)
+	h := ga.HallOfFame()
+	if len(h) != 5 {
+		t.Fatalf("len(HallOfFame()) = %d; want 5", len(h))
+	}
+	if h[0].Score() != ga.Best().Score() {
+		t.Errorf("HallOfFame()[0] score = %v; want best %v", h[0].Score(), ga.Best().Score())
+	}
+	for i := 1; i < len(h); i++ {
+		if h[i].Score() < h[i-1].Score() || h[i].String() == h[i-1].String() {
+			t.Errorf("HallOfFame() not sorted and distinct: %v", h)
+		}
+	}
+
+	gap := NewGAParallel(param, 3)
+	gap.Init(20, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	gap.Optimize(5)
+	if h := gap.HallOfFame(); len(h) != 5 || h[0].Score() > gap.Best().Score() {
+		t.Errorf("GAParallel.HallOfFame() = %v; want 5 genomes led by the best", h)
+	}
+}
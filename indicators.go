@@ -0,0 +1,343 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Quality indicators of Pareto fronts, every objective minimized.
+*/
+
+package ga
+
+import (
+	"math"
+	"sort"
+)
+
+// Number of samples Hypervolume uses for more than 3 objectives.
+const HypervolumeSamples = 100000
+
+// Hypervolume returns the volume of the part of objective space dominated
+// by the front and bounded by the reference point. Points not dominating
+// the reference point add nothing. It is exact for up to 3 objectives and
+// estimated from HypervolumeSamples random points for more.
+func Hypervolume(front [][]float64, ref []float64) float64 {
+	front = bounded(front, ref)
+	if len(front) == 0 {
+		return 0
+	}
+	switch len(ref) {
+	case 1:
+		lo := math.Inf(1)
+		for _, f := range front {
+			lo = math.Min(lo, f[0])
+		}
+		return ref[0] - lo
+	case 2:
+		return hypervolume2(front, ref)
+	case 3:
+		return hypervolume3(front, ref)
+	}
+	return HypervolumeMonteCarlo(front, ref, HypervolumeSamples)
+}
+
+// bounded returns the points of front that dominate ref in every objective.
+func bounded(front [][]float64, ref []float64) [][]float64 {
+	var b [][]float64
+	for _, f := range front {
+		in := true
+		for k := range ref {
+			in = in && f[k] < ref[k]
+		}
+		if in {
+			b = append(b, f)
+		}
+	}
+	return b
+}
+
+// hypervolume2 sweeps the points by the first objective.
+func hypervolume2(front [][]float64, ref []float64) float64 {
+	sorted := make([][]float64, len(front))
+	copy(sorted, front)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	hv, y := 0.0, ref[1]
+	for _, f := range sorted {
+		if f[1] < y {
+			hv += (ref[0] - f[0]) * (y - f[1])
+			y = f[1]
+		}
+	}
+	return hv
+}
+
+// hypervolume3 adds up slices between the third objectives of the points,
+// each the 2 objective hypervolume of the points below it.
+func hypervolume3(front [][]float64, ref []float64) float64 {
+	sorted := make([][]float64, len(front))
+	copy(sorted, front)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][2] < sorted[j][2] })
+	hv := 0.0
+	for i := range sorted {
+		top := ref[2]
+		if i+1 < len(sorted) {
+			top = sorted[i+1][2]
+		}
+		if top > sorted[i][2] {
+			hv += hypervolume2(sorted[:i+1], ref) * (top - sorted[i][2])
+		}
+	}
+	return hv
+}
+
+// HypervolumeMonteCarlo estimates the hypervolume of the front from the
+// share of samples random points in the box between the front and the
+// reference point that the front dominates.
+func HypervolumeMonteCarlo(front [][]float64, ref []float64, samples int) float64 {
+	front = bounded(front, ref)
+	if len(front) == 0 || samples <= 0 {
+		return 0
+	}
+	lo := make([]float64, len(ref))
+	volume := 1.0
+	for k := range ref {
+		lo[k] = math.Inf(1)
+		for _, f := range front {
+			lo[k] = math.Min(lo[k], f[k])
+		}
+		volume *= ref[k] - lo[k]
+	}
+	x := make([]float64, len(ref))
+	hits := 0
+	for s := 0; s < samples; s++ {
+		for k := range x {
+			x[k] = lo[k] + rng.Float64()*(ref[k]-lo[k])
+		}
+		for _, f := range front {
+			dominated := true
+			for k := range x {
+				if f[k] > x[k] {
+					dominated = false
+					break
+				}
+			}
+			if dominated {
+				hits++
+				break
+			}
+		}
+	}
+	return volume * float64(hits) / float64(samples)
+}
+
+// HypervolumeContributions returns for every point of the front the
+// hypervolume only it dominates, the hypervolume lost without it. It is
+// exact for up to 3 objectives and estimated from HypervolumeSamples random
+// points for more.
+func HypervolumeContributions(front [][]float64, ref []float64) []float64 {
+	if len(front) == 0 {
+		return nil
+	}
+	switch len(ref) {
+	case 1, 3:
+		return leaveOneOut(front, ref)
+	case 2:
+		return contributions2(front, ref)
+	}
+	return contributionsMonteCarlo(front, ref, HypervolumeSamples)
+}
+
+// leaveOneOut returns the hypervolume of the front less that of the front
+// without each point.
+func leaveOneOut(front [][]float64, ref []float64) []float64 {
+	total := Hypervolume(front, ref)
+	c := make([]float64, len(front))
+	rest := make([][]float64, 0, len(front)-1)
+	for i := range front {
+		rest = append(append(rest[:0], front[:i]...), front[i+1:]...)
+		c[i] = total - Hypervolume(rest, ref)
+	}
+	return c
+}
+
+// contributions2 sweeps the points by the first objective. In a front of
+// points that do not dominate each other a point alone dominates the box
+// between it, the next point and the previous one, and copies of a point
+// dominate nothing alone. Fronts with dominated points are left to
+// leaveOneOut, as a point it dominates covers part of the box of a point.
+func contributions2(front [][]float64, ref []float64) []float64 {
+	idx := make([]int, 0, len(front))
+	for i, f := range front {
+		if f[0] < ref[0] && f[1] < ref[1] {
+			idx = append(idx, i)
+		}
+	}
+	sort.Slice(idx, func(a, b int) bool {
+		f, g := front[idx[a]], front[idx[b]]
+		return f[0] < g[0] || f[0] == g[0] && f[1] < g[1]
+	})
+	var skeleton []int
+	repeated := make(map[int]bool)
+	y := ref[1]
+	for _, i := range idx {
+		if front[i][1] < y {
+			y = front[i][1]
+			skeleton = append(skeleton, i)
+			continue
+		}
+		last := front[skeleton[len(skeleton)-1]]
+		if front[i][0] != last[0] || front[i][1] != last[1] {
+			return leaveOneOut(front, ref)
+		}
+		repeated[skeleton[len(skeleton)-1]] = true
+	}
+	c := make([]float64, len(front))
+	for k, i := range skeleton {
+		if repeated[i] {
+			continue
+		}
+		right, up := ref[0], ref[1]
+		if k+1 < len(skeleton) {
+			right = front[skeleton[k+1]][0]
+		}
+		if k > 0 {
+			up = front[skeleton[k-1]][1]
+		}
+		c[i] = (right - front[i][0]) * (up - front[i][1])
+	}
+	return c
+}
+
+// contributionsMonteCarlo estimates the contributions from samples random
+// points in the box between the front and the reference point. A sample
+// counts for a point if no other point dominates it, so every contribution
+// is estimated directly from the same samples.
+func contributionsMonteCarlo(front [][]float64, ref []float64, samples int) []float64 {
+	c := make([]float64, len(front))
+	var idx []int
+	for i, f := range front {
+		in := true
+		for k := range ref {
+			in = in && f[k] < ref[k]
+		}
+		if in {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 || samples <= 0 {
+		return c
+	}
+	lo := make([]float64, len(ref))
+	volume := 1.0
+	for k := range ref {
+		lo[k] = math.Inf(1)
+		for _, i := range idx {
+			lo[k] = math.Min(lo[k], front[i][k])
+		}
+		volume *= ref[k] - lo[k]
+	}
+	x := make([]float64, len(ref))
+	hits := make([]int, len(front))
+	for s := 0; s < samples; s++ {
+		for k := range x {
+			x[k] = lo[k] + rng.Float64()*(ref[k]-lo[k])
+		}
+		only := -1
+		for _, i := range idx {
+			dominated := true
+			for k := range x {
+				if front[i][k] > x[k] {
+					dominated = false
+					break
+				}
+			}
+			if !dominated {
+				continue
+			}
+			if only >= 0 {
+				only = -1
+				break
+			}
+			only = i
+		}
+		if only >= 0 {
+			hits[only]++
+		}
+	}
+	for i, h := range hits {
+		c[i] = volume * float64(h) / float64(samples)
+	}
+	return c
+}
+
+func distance(a, b []float64) float64 {
+	var d float64
+	for k := range a {
+		d += (a[k] - b[k]) * (a[k] - b[k])
+	}
+	return math.Sqrt(d)
+}
+
+// nearest returns the distance from p to the nearest point of front.
+func nearest(p []float64, front [][]float64) float64 {
+	d := math.Inf(1)
+	for _, f := range front {
+		d = math.Min(d, distance(p, f))
+	}
+	return d
+}
+
+// IGD returns the inverted generational distance of the front, the mean
+// distance from the points of a reference front, usually many points of the
+// true Pareto front, to the nearest point of the front. Lower is better, it
+// measures both how close and how spread the front is.
+func IGD(front, reference [][]float64) float64 {
+	if len(reference) == 0 {
+		return 0
+	}
+	var s float64
+	for _, r := range reference {
+		s += nearest(r, front)
+	}
+	return s / float64(len(reference))
+}
+
+// Spread returns the generalized spread of the front: 0 when its points are
+// evenly spaced and reach the extremes of the reference front, larger when
+// they bunch up or stop short. Without a reference front only the spacing
+// counts.
+func Spread(front, reference [][]float64) float64 {
+	if len(front) < 2 {
+		return 0
+	}
+	var extremes float64
+	if len(reference) > 0 {
+		for k := range reference[0] {
+			e := reference[0]
+			for _, r := range reference {
+				if r[k] < e[k] {
+					e = r
+				}
+			}
+			extremes += nearest(e, front)
+		}
+	}
+	d := make([]float64, len(front))
+	var mean float64
+	for i, f := range front {
+		d[i] = math.Inf(1)
+		for j, g := range front {
+			if i != j {
+				d[i] = math.Min(d[i], distance(f, g))
+			}
+		}
+		mean += d[i]
+	}
+	mean /= float64(len(front))
+	dev := 0.0
+	for _, x := range d {
+		dev += math.Abs(x - mean)
+	}
+	if extremes+float64(len(front))*mean == 0 {
+		return 0
+	}
+	return (extremes + dev) / (extremes + float64(len(front))*mean)
+}
@@ -0,0 +1,106 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHypervolume(t *testing.T) {
+	tests := []struct {
+		front [][]float64
+		ref   []float64
+		want  float64
+	}{
+		{[][]float64{{1, 3}, {2, 2}, {3, 1}}, []float64{4, 4}, 6},
+		// Dominated points and points beyond the reference add nothing.
+		{[][]float64{{1, 3}, {2, 2}, {3, 1}, {3, 3}, {5, 0}}, []float64{4, 4}, 6},
+		{[][]float64{{0, 0, 0}}, []float64{1, 1, 1}, 1},
+		{[][]float64{{0, 0, 0.5}, {0.5, 0.5, 0}}, []float64{1, 1, 1}, 0.625},
+		{nil, []float64{1, 1}, 0},
+	}
+	for _, test := range tests {
+		if hv := Hypervolume(test.front, test.ref); math.Abs(hv-test.want) > 1e-12 {
+			t.Errorf("Hypervolume(%v, %v) = %v; want %v", test.front, test.ref, hv, test.want)
+		}
+	}
+
+	front := [][]float64{{0, 0, 0.5}, {0.5, 0.5, 0}, {0.2, 0.7, 0.3}}
+	exact := Hypervolume(front, []float64{1, 1, 1})
+	if hv := HypervolumeMonteCarlo(front, []float64{1, 1, 1}, 100000); math.Abs(hv-exact) > 0.01 {
+		t.Errorf("HypervolumeMonteCarlo() = %v; want about %v", hv, exact)
+	}
+	if hv := Hypervolume([][]float64{{0.5, 0.5, 0.5, 0.5}}, []float64{1, 1, 1, 1}); math.Abs(hv-0.0625) > 0.005 {
+		t.Errorf("Hypervolume() of 4 objectives = %v; want about 0.0625", hv)
+	}
+}
+
+func TestHypervolumeContributions(t *testing.T) {
+	for _, front := range [][][]float64{
+		{{1, 3}, {2, 2}, {3, 1}, {3, 3}, {2, 2}, {0.5, 3.5}},
+		{{0, 0, 0.5}, {0.5, 0.5, 0}, {0.2, 0.7, 0.3}},
+	} {
+		ref := make([]float64, len(front[0]))
+		for k := range ref {
+			ref[k] = 4
+		}
+		c := HypervolumeContributions(front, ref)
+		total := Hypervolume(front, ref)
+		for i := range front {
+			rest := append(append([][]float64(nil), front[:i]...), front[i+1:]...)
+			if want := total - Hypervolume(rest, ref); math.Abs(c[i]-want) > 1e-12 {
+				t.Errorf("contribution of %v in %v = %v; want %v", front[i], front, c[i], want)
+			}
+		}
+	}
+
+	tests := []struct {
+		front [][]float64
+		ref   []float64
+		want  []float64
+	}{
+		// A point covers part of the box of a point dominating it.
+		{[][]float64{{1, 2}, {2, 2}}, []float64{4, 4}, []float64{2, 0}},
+		{[][]float64{{1, 1}, {2, 2}}, []float64{4, 4}, []float64{5, 0}},
+		{[][]float64{{1, 3}, {3, 1}, {2, 2}, {3, 3}}, []float64{4, 4}, []float64{1, 1, 1, 0}},
+		// Estimated from samples for more than 3 objectives.
+		{[][]float64{{0.5, 0.5, 0.5, 0.5}, {0.25, 0.75, 0.5, 0.5}, {2, 0, 0, 0}}, []float64{1, 1, 1, 1}, []float64{0.03125, 0.015625, 0}},
+	}
+	for _, test := range tests {
+		c := HypervolumeContributions(test.front, test.ref)
+		for i := range test.want {
+			if math.Abs(c[i]-test.want[i]) > 1e-3 {
+				t.Errorf("HypervolumeContributions(%v, %v) = %v; want %v", test.front, test.ref, c, test.want)
+				break
+			}
+		}
+	}
+	for _, ref := range [][]float64{{1}, {1, 1}, {1, 1, 1}, {1, 1, 1, 1}} {
+		if c := HypervolumeContributions(nil, ref); c != nil {
+			t.Errorf("HypervolumeContributions of no points in %d objectives = %v; want nil", len(ref), c)
+		}
+	}
+}
+
+func TestIGDAndSpread(t *testing.T) {
+	var reference, half [][]float64
+	for i := 0; i <= 100; i++ {
+		x := float64(i) / 100
+		reference = append(reference, []float64{x, 1 - x})
+		if x <= 0.5 {
+			half = append(half, []float64{x, 1 - x})
+		}
+	}
+	if igd := IGD(reference, reference); igd != 0 {
+		t.Errorf("IGD of the reference front = %v; want 0", igd)
+	}
+	if IGD(half, reference) <= 0.1 {
+		t.Errorf("IGD of half the front = %v; want more than 0.1", IGD(half, reference))
+	}
+	if s := Spread(reference, reference); s > 1e-9 {
+		t.Errorf("Spread of the reference front = %v; want 0", s)
+	}
+	bunched := append(append([][]float64(nil), half...), []float64{0.9, 0.1})
+	if Spread(bunched, reference) <= Spread(half, nil) {
+		t.Errorf("Spread of a bunched front %v; want more than %v", Spread(bunched, reference), Spread(half, nil))
+	}
+}
@@ -8,6 +8,12 @@ go-galib initializers
 
 package ga
 
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
 type GAInitializer interface {
 	// Initializes popsize length []GAGenome from i
 	InitPop(i GAGenome, popsize int) []GAGenome
@@ -28,6 +34,7 @@ func (i *GARandomInitializer) InitPop(first GAGenome, popsize int) (pop []GAGeno
 
 func (i *GARandomInitializer) String() string { return "RandomInitializer" }
 
+// Fills the population with copies of one random genome.
 type GAHRandomInitializer struct{}
 
 func (i *GAHRandomInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
@@ -39,4 +46,204 @@ func (i *GAHRandomInitializer) InitPop(first GAGenome, popsize int) (pop []GAGen
 	return pop
 }
 
-func (i *GAHRandomInitializer) String() string { return "RandomInitializer" }
+func (i *GAHRandomInitializer) String() string { return "HRandomInitializer" }
+
+// Starts the population with copies of Seeds, known good genomes, and fills
+// the rest with random genomes. If there are more seeds than the population
+// size only the first ones are used. A GARestart using it puts the seeds
+// back every time, unless it has an Initializer of its own.
+type GASeededInitializer struct {
+	Seeds GAGenomes
+}
+
+func NewGASeededInitializer(seeds ...GAGenome) *GASeededInitializer {
+	return &GASeededInitializer{Seeds: seeds}
+}
+
+func (i *GASeededInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = make([]GAGenome, popsize)
+	for x := 0; x < popsize; x++ {
+		if x < len(i.Seeds) {
+			pop[x] = i.Seeds[x].Copy()
+			continue
+		}
+		pop[x] = first.Copy()
+		pop[x].Randomize()
+	}
+	return pop
+}
+
+func (i *GASeededInitializer) String() string { return "SeededInitializer" }
+
+// Opposition based initialization. Makes popsize random genomes and their
+// opposites and keeps the popsize best of them. The opposite of a gene x in
+// [Min,Max] is Min+Max-x, the opposite of a bit its complement and the
+// opposite of a permutation its reverse.
+type GAOppositionInitializer struct {
+	// Scores the genomes, usually the Evaluator of the GAParameter. nil
+	// uses the Score method of the genomes. If it fails the random genomes
+	// are returned without their opposites and unscored.
+	Evaluator GAEvaluator
+}
+
+func (i *GAOppositionInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	both := make(GAGenomes, 0, 2*popsize)
+	for x := 0; x < popsize; x++ {
+		g := first.Copy()
+		g.Randomize()
+		both = append(both, g, opposite(g))
+	}
+	if i.Evaluator != nil && i.Evaluator.Evaluate(both) != nil {
+		pop = make([]GAGenome, popsize)
+		for x := range pop {
+			pop[x] = both[2*x]
+		}
+		return pop
+	}
+	sort.Sort(both)
+	return both[:popsize]
+}
+
+func (i *GAOppositionInitializer) String() string { return "OppositionInitializer" }
+
+// opposite returns the opposite of a built-in genome.
+func opposite(g GAGenome) GAGenome {
+	o := g.Copy()
+	switch o := o.(type) {
+	case *GAFixedBitstringGenome:
+		for i := range o.Gene {
+			o.Gene[i] = !o.Gene[i]
+		}
+	case *GAIntGenome:
+		for i := range o.Gene {
+			o.Gene[i] = o.min + o.max - o.Gene[i]
+		}
+	case *GAOrderedIntGenome:
+		reverse(o, 0, len(o.Gene)-1)
+	case *GAFloatGenome:
+		for i := range o.Gene {
+			o.Gene[i] = o.Min + o.Max - o.Gene[i]
+		}
+	case *GAFloat32Genome:
+		for i := range o.Gene {
+			o.Gene[i] = o.Min + o.Max - o.Gene[i]
+		}
+	default:
+		panic("Opposition initializer needs a built-in genome")
+	}
+	o.Reset()
+	return o
+}
+
+// Greedy nearest neighbour tours for GAOrderedIntGenome. Every tour starts
+// at a random city and moves on to the nearest city not visited yet, or with
+// chance PRandom to a random one so the tours differ. Dist[a][b] is the
+// distance between the cities a and b, the genes of the genome.
+type GANearestNeighbourInitializer struct {
+	Dist    [][]float64
+	PRandom float64
+}
+
+func NewGANearestNeighbourInitializer(dist [][]float64, prandom float64) *GANearestNeighbourInitializer {
+	return &GANearestNeighbourInitializer{Dist: dist, PRandom: prandom}
+}
+
+func (i *GANearestNeighbourInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = make([]GAGenome, popsize)
+	n := len(i.Dist)
+	for x := 0; x < popsize; x++ {
+		g := first.Copy().(*GAOrderedIntGenome)
+		if len(g.Gene) != n {
+			panic("Nearest neighbour initializer needs one gene per city")
+		}
+		if n == 0 {
+			// No cities, no tour to build.
+			pop[x] = g
+			continue
+		}
+		unvisited := make([]int, n)
+		for c := range unvisited {
+			unvisited[c] = c
+		}
+		k := rng.Intn(n)
+		for j := 0; j < n; j++ {
+			c := unvisited[k]
+			g.Gene[j] = c
+			unvisited[k] = unvisited[len(unvisited)-1]
+			unvisited = unvisited[:len(unvisited)-1]
+			if len(unvisited) == 0 {
+				break
+			}
+			if i.PRandom > rng.Float64() {
+				k = rng.Intn(len(unvisited))
+				continue
+			}
+			k = 0
+			for u := range unvisited {
+				if i.Dist[c][unvisited[u]] < i.Dist[c][unvisited[k]] {
+					k = u
+				}
+			}
+		}
+		g.Reset()
+		pop[x] = g
+	}
+	return pop
+}
+
+func (i *GANearestNeighbourInitializer) String() string { return "NearestNeighbourInitializer" }
+
+// Seeds the population with genomes read from a file written by
+// GA.ExportPopulation or by hand, see population_io.go. The genes are read
+// into copies of the genome passed to NewGAImportInitializer, so they get
+// its score function and range. If the file holds fewer genomes than the
+// population size the rest are random copies of the genome passed to
+// InitPop. A GARestart using it puts the same genomes back every time,
+// unless it has an Initializer of its own.
+type GAImportInitializer struct {
+	genomes GAGenomes
+}
+
+// NewGAImportInitializer reads the genomes in r into copies of g. Every
+// genome must have as many genes as g.
+func NewGAImportInitializer(r io.Reader, format GAFormat, g GAGenome) (*GAImportInitializer, error) {
+	rows, arrays, err := readGenes(r, format)
+	if err != nil {
+		return nil, err
+	}
+	i := new(GAImportInitializer)
+	for x := 0; x < len(rows)+len(arrays); x++ {
+		c := g.Copy()
+		if x < len(rows) {
+			err = setGeneStrings(c, rows[x])
+		} else {
+			err = setGeneJSON(c, arrays[x-len(rows)])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ga: genome %d: %v", x+1, err)
+		}
+		if c.Len() != g.Len() {
+			return nil, fmt.Errorf("ga: genome %d has %d genes; want %d", x+1, c.Len(), g.Len())
+		}
+		i.genomes = append(i.genomes, c)
+	}
+	return i, nil
+}
+
+// Len returns the number of genomes read.
+func (i *GAImportInitializer) Len() int { return len(i.genomes) }
+
+func (i *GAImportInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = make([]GAGenome, popsize)
+	for x := 0; x < popsize; x++ {
+		if x < len(i.genomes) {
+			pop[x] = i.genomes[x].Copy()
+			continue
+		}
+		pop[x] = first.Copy()
+		pop[x].Randomize()
+	}
+	return pop
+}
+
+func (i *GAImportInitializer) String() string { return "ImportInitializer" }
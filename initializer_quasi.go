@@ -0,0 +1,186 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Initializers for GAFloatGenome and GAFloat32Genome that spread the
+population evenly over [Min,Max] instead of sampling it at random.
+*/
+
+package ga
+
+// Latin hypercube sampling. Every gene range is cut into popsize equal
+// strata and every stratum of every gene is used by exactly one genome.
+type GALatinHypercubeInitializer struct{}
+
+func (i *GALatinHypercubeInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = copies(first, popsize)
+	for d := 0; d < first.Len(); d++ {
+		perm := rng.Perm(popsize)
+		for x := range pop {
+			setUnit(pop[x], d, (float64(perm[x])+rng.Float64())/float64(popsize))
+		}
+	}
+	return pop
+}
+
+func (i *GALatinHypercubeInitializer) String() string { return "LatinHypercubeInitializer" }
+
+// Halton sequence, using the d'th prime as base for gene d. Skip leaves out
+// the first points of the sequence.
+type GAHaltonInitializer struct {
+	Skip int
+}
+
+func (i *GAHaltonInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = copies(first, popsize)
+	primes := firstPrimes(first.Len())
+	for x := range pop {
+		for d, p := range primes {
+			setUnit(pop[x], d, radicalInverse(x+1+i.Skip, p))
+		}
+	}
+	return pop
+}
+
+func (i *GAHaltonInitializer) String() string { return "HaltonInitializer" }
+
+// radicalInverse mirrors the digits of n in base b around the radix point.
+func radicalInverse(n, b int) float64 {
+	r, f := 0.0, 1/float64(b)
+	for ; n > 0; n /= b {
+		r += float64(n%b) * f
+		f /= float64(b)
+	}
+	return r
+}
+
+func firstPrimes(n int) []int {
+	primes := make([]int, 0, n)
+	for c := 2; len(primes) < n; c++ {
+		prime := true
+		for _, p := range primes {
+			if p*p > c {
+				break
+			}
+			if c%p == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			primes = append(primes, c)
+		}
+	}
+	return primes
+}
+
+// Sobol sequence with the direction numbers of Joe and Kuo. Genes past the
+// 21 dimensions of the table get uniform random values. Skip leaves out the
+// first points of the sequence.
+type GASobolInitializer struct {
+	Skip int
+}
+
+// Degree s, coefficients a and initial direction numbers m of the
+// primitive polynomials for dimensions 2 to 21.
+var sobolTable = []struct {
+	s, a uint
+	m    []uint32
+}{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
+
+// sobolDirections returns the 32 direction numbers of dimension d.
+func sobolDirections(d int) []uint32 {
+	v := make([]uint32, 33)
+	if d == 0 {
+		for i := uint(1); i <= 32; i++ {
+			v[i] = 1 << (32 - i)
+		}
+		return v
+	}
+	t := sobolTable[d-1]
+	for i := uint(1); i <= 32; i++ {
+		if i <= t.s {
+			v[i] = t.m[i-1] << (32 - i)
+			continue
+		}
+		v[i] = v[i-t.s] ^ (v[i-t.s] >> t.s)
+		for k := uint(1); k < t.s; k++ {
+			v[i] ^= ((uint32(t.a) >> (t.s - 1 - k)) & 1) * v[i-k]
+		}
+	}
+	return v
+}
+
+func (i *GASobolInitializer) InitPop(first GAGenome, popsize int) (pop []GAGenome) {
+	pop = copies(first, popsize)
+	for d := 0; d < first.Len(); d++ {
+		if d > len(sobolTable) {
+			for x := range pop {
+				setUnit(pop[x], d, rng.Float64())
+			}
+			continue
+		}
+		v := sobolDirections(d)
+		// Gray code construction, point n differs from point n-1 by the
+		// direction number of the lowest zero bit of n-1.
+		var x uint32
+		for n := 1; n <= i.Skip+popsize; n++ {
+			c, m := 1, n-1
+			for m&1 == 1 {
+				m >>= 1
+				c++
+			}
+			x ^= v[c]
+			if n > i.Skip {
+				setUnit(pop[n-1-i.Skip], d, float64(x)/(1<<32))
+			}
+		}
+	}
+	return pop
+}
+
+func (i *GASobolInitializer) String() string { return "SobolInitializer" }
+
+// copies returns n copies of g.
+func copies(g GAGenome, n int) []GAGenome {
+	pop := make([]GAGenome, n)
+	for x := range pop {
+		pop[x] = g.Copy()
+		pop[x].Reset()
+	}
+	return pop
+}
+
+// setUnit sets gene i of a real valued genome to the point u of [0,1) mapped
+// onto [Min,Max].
+func setUnit(g GAGenome, i int, u float64) {
+	switch g := g.(type) {
+	case *GAFloatGenome:
+		g.Gene[i] = g.Min + u*(g.Max-g.Min)
+	case *GAFloat32Genome:
+		g.Gene[i] = g.Min + float32(u)*(g.Max-g.Min)
+	default:
+		panic("Quasi random initializer needs a GAFloatGenome or GAFloat32Genome")
+	}
+}
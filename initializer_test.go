@@ -0,0 +1,135 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+// Tests that the initializers spreading genes over the range put every gene
+// of the population in its own one of 32 strata. The Sobol sequence leaves
+// out the point at 0, so only the next 31 points are stratified.
+func TestQuasiInitializersStratified(t *testing.T) {
+	const strata, l = 32, 21
+	for _, c := range []struct {
+		init    GAInitializer
+		popsize int
+	}{
+		{new(GALatinHypercubeInitializer), strata},
+		{new(GASobolInitializer), strata - 1},
+	} {
+		pop := c.init.InitPop(NewFloatGenome(make([]float64, l), nil, 5, -3), c.popsize)
+		for d := 0; d < l; d++ {
+			used := make([]bool, strata)
+			for _, g := range pop {
+				x := g.(*GAFloatGenome).Gene[d]
+				s := int(math.Floor((x + 3) / 8 * strata))
+				if s < 0 || s >= strata || used[s] {
+					t.Fatalf("%s: gene %d = %v in stratum %d used twice or out of range", c.init, d, x, s)
+				}
+				used[s] = true
+			}
+		}
+	}
+}
+
+func TestSobolInitializer(t *testing.T) {
+	want := [][2]float64{
+		{0.5, 0.5}, {0.75, 0.25}, {0.25, 0.75}, {0.375, 0.375},
+		{0.875, 0.875}, {0.625, 0.125}, {0.125, 0.625},
+	}
+	pop := new(GASobolInitializer).InitPop(NewFloatGenome(make([]float64, 2), nil, 1, 0), len(want))
+	for i, w := range want {
+		if g := pop[i].(*GAFloatGenome).Gene; g[0] != w[0] || g[1] != w[1] {
+			t.Errorf("Sobol point %d = %v; want %v", i+1, g, w)
+		}
+	}
+	skipped := (&GASobolInitializer{Skip: 3}).InitPop(NewFloatGenome(make([]float64, 2), nil, 1, 0), 1)
+	if g := skipped[0].(*GAFloatGenome).Gene; g[0] != want[3][0] || g[1] != want[3][1] {
+		t.Errorf("Sobol point 4 with Skip 3 = %v; want %v", g, want[3])
+	}
+}
+
+func TestHaltonInitializer(t *testing.T) {
+	want := [][2]float64{{0.5, 1.0 / 3}, {0.25, 2.0 / 3}, {0.75, 1.0 / 9}}
+	pop := new(GAHaltonInitializer).InitPop(NewFloat32Genome(make([]float32, 2), nil, 1, 0), len(want))
+	for i, w := range want {
+		g := pop[i].(*GAFloat32Genome).Gene
+		if math.Abs(float64(g[0])-w[0]) > 1e-6 || math.Abs(float64(g[1])-w[1]) > 1e-6 {
+			t.Errorf("Halton point %d = %v; want %v", i+1, g, w)
+		}
+	}
+}
+
+func TestOppositionInitializer(t *testing.T) {
+	g := NewIntGenome([]int{0}, firstGene, 0, 10)
+	if o := opposite(g).(*GAIntGenome); o.Gene[0] != 10 {
+		t.Errorf("opposite(%v) = %v; want [10]", g, o)
+	}
+	pop := new(GAOppositionInitializer).InitPop(NewIntGenome(make([]int, 1), firstGene, 0, 10), 10)
+	if len(pop) != 10 {
+		t.Fatalf("len(InitPop) = %d; want 10", len(pop))
+	}
+	for _, g := range pop {
+		// Of x and 10-x the better one is at most 5.
+		if g.Score() > 5 {
+			t.Errorf("OppositionInitializer kept %v; want the better of each pair", g)
+		}
+	}
+}
+
+func TestOppositionInitializerEvaluator(t *testing.T) {
+	// The genomes have no score function of their own.
+	init := &GAOppositionInitializer{Evaluator: &failingEvaluator{after: 1}}
+	pop := init.InitPop(NewFloatGenome(make([]float64, 2), nil, 10, 0), 10)
+	for i, g := range pop {
+		if !g.(GAScoreSetter).HasScore() || i > 0 && g.Score() < pop[i-1].Score() {
+			t.Fatalf("InitPop through the Evaluator = %v; want scored genomes, best first", pop)
+		}
+	}
+	pop = init.InitPop(NewFloatGenome(make([]float64, 2), nil, 10, 0), 10)
+	if len(pop) != 10 {
+		t.Errorf("InitPop after the Evaluator failed returned %d genomes; want 10", len(pop))
+	}
+}
+
+func TestSeededInitializer(t *testing.T) {
+	seed := NewFixedBitstringGenome([]bool{true, true, true}, bitsScore)
+	pop := NewGASeededInitializer(seed).InitPop(NewFixedBitstringGenome(make([]bool, 3), bitsScore), 5)
+	if pop[0].String() != seed.String() || pop[0] == GAGenome(seed) {
+		t.Errorf("pop[0] = %v; want a copy of the seed", pop[0])
+	}
+	if len(pop) != 5 {
+		t.Errorf("len(InitPop) = %d; want 5", len(pop))
+	}
+}
+
+func TestNearestNeighbourInitializer(t *testing.T) {
+	dist := circle(12)
+	length := func(g *GAOrderedIntGenome) float64 {
+		var s float64
+		for i := range g.Gene {
+			s += dist[g.Gene[i]][g.Gene[(i+1)%len(g.Gene)]]
+		}
+		return s
+	}
+	best := length(NewOrderedIntGenome(identity(12), nil))
+	pop := NewGANearestNeighbourInitializer(dist, 0).InitPop(NewOrderedIntGenome(identity(12), length), 5)
+	for _, g := range pop {
+		if !isPermutation(g.(*GAOrderedIntGenome).Gene) {
+			t.Fatalf("InitPop made %v; want a permutation", g)
+		}
+		// On a circle the nearest neighbour tour is optimal.
+		if s := g.Score(); s > best+1e-9 {
+			t.Errorf("nearest neighbour tour %v length %v; want %v", g, s, best)
+		}
+	}
+	for _, g := range NewGANearestNeighbourInitializer(dist, 0.5).InitPop(NewOrderedIntGenome(identity(12), length), 5) {
+		if !isPermutation(g.(*GAOrderedIntGenome).Gene) {
+			t.Fatalf("InitPop made %v; want a permutation", g)
+		}
+	}
+	// Without cities every genome is an empty tour.
+	if pop := NewGANearestNeighbourInitializer(nil, 0).InitPop(NewOrderedIntGenome(nil, length), 3); len(pop) != 3 || pop[0].Len() != 0 {
+		t.Errorf("InitPop without cities = %v; want 3 empty tours", pop)
+	}
+}
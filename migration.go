@@ -0,0 +1,235 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Migration between the islands of a GAParallel. Every Interval generations
+each island sends Size emigrants to the islands its topology connects it
+to, where they take the place of resident genomes.
+*/
+
+package ga
+
+import (
+	"sort"
+)
+
+type GATopology interface {
+	// Targets returns the islands island i of n sends emigrants to.
+	Targets(i, n int) []int
+	// String name of topology
+	String() string
+}
+
+// Island i sends to island i+1, the last island to the first.
+type GARingTopology struct{}
+
+func (t *GARingTopology) Targets(i, n int) []int {
+	if n < 2 {
+		return nil
+	}
+	return []int{(i + 1) % n}
+}
+
+func (t *GARingTopology) String() string { return "GARingTopology" }
+
+// The Hub island exchanges genomes with every other island, the others only
+// with the hub.
+type GAStarTopology struct {
+	// Number of the hub island, below the number of islands
+	Hub int
+}
+
+func (t *GAStarTopology) Targets(i, n int) []int {
+	if t.Hub < 0 || t.Hub >= n {
+		panic("Star topology hub is not an island")
+	}
+	if i != t.Hub {
+		return []int{t.Hub}
+	}
+	targets := make([]int, 0, n-1)
+	for j := 0; j < n; j++ {
+		if j != t.Hub {
+			targets = append(targets, j)
+		}
+	}
+	return targets
+}
+
+func (t *GAStarTopology) String() string { return "GAStarTopology" }
+
+// Every island sends to every other island.
+type GAFullTopology struct{}
+
+func (t *GAFullTopology) Targets(i, n int) []int {
+	targets := make([]int, 0, n-1)
+	for j := 0; j < n; j++ {
+		if j != i {
+			targets = append(targets, j)
+		}
+	}
+	return targets
+}
+
+func (t *GAFullTopology) String() string { return "GAFullTopology" }
+
+// Every island sends to Degree other islands drawn at random at every
+// migration, one if Degree is not set.
+type GARandomTopology struct {
+	Degree int
+}
+
+func (t *GARandomTopology) Targets(i, n int) []int {
+	k := t.Degree
+	if k < 1 {
+		k = 1
+	}
+	if k > n-1 {
+		k = n - 1
+	}
+	targets := make([]int, 0, k)
+	for _, j := range rng.Perm(n - 1)[:k] {
+		if j >= i {
+			j++
+		}
+		targets = append(targets, j)
+	}
+	return targets
+}
+
+func (t *GARandomTopology) String() string { return "GARandomTopology" }
+
+// Islands are the corners of a hypercube, island i sends to the islands
+// whose number differs from i in one bit. With a number of islands that is
+// not a power of two the missing corners are left out.
+type GAHypercubeTopology struct{}
+
+func (t *GAHypercubeTopology) Targets(i, n int) []int {
+	var targets []int
+	for b := 1; b < n; b <<= 1 {
+		if j := i ^ b; j < n {
+			targets = append(targets, j)
+		}
+	}
+	return targets
+}
+
+func (t *GAHypercubeTopology) String() string { return "GAHypercubeTopology" }
+
+// Which genomes of an island are sent as emigrants.
+type GAEmigration int
+
+const (
+	// Send copies of the best genomes.
+	GAEmigrateBest GAEmigration = iota
+	// Send copies of genomes drawn at random.
+	GAEmigrateRandom
+	// Send copies of genomes picked by the Selector of the island.
+	GAEmigrateSelect
+)
+
+// Which genomes of an island immigrants take the place of.
+type GAImmigration int
+
+const (
+	// Replace the worst genomes.
+	GAImmigrateWorst GAImmigration = iota
+	// Replace genomes drawn at random, the best genome is never replaced.
+	GAImmigrateRandom
+	// Replace the worst genomes, but only by better immigrants.
+	GAImmigrateBetter
+)
+
+type GAMigration struct {
+	// Islands each island sends emigrants to, nil disables migration
+	Topology GATopology
+	// Generations between migrations, 0 disables migration as a nil
+	// Topology does
+	Interval int
+	// Number of emigrants sent to each target island
+	Size       int
+	Emigrants  GAEmigration
+	Immigrants GAImmigration
+	// Turns an emigrant of island from into a genome for island to, for
+	// islands using different genome encodings. nil moves genomes as they
+	// are.
+	Convert func(g GAGenome, from, to int) GAGenome
+}
+
+// emigrants returns copies of m.Size genomes of the island.
+func (m *GAMigration) emigrants(ga *GA) GAGenomes {
+	n := m.Size
+	if n > len(ga.pop) {
+		n = len(ga.pop)
+	}
+	out := make(GAGenomes, n)
+	switch m.Emigrants {
+	case GAEmigrateBest:
+		sort.Sort(ga.pop)
+		for i := range out {
+			out[i] = ga.pop[i].Copy()
+		}
+	case GAEmigrateRandom:
+		for i, j := range rng.Perm(len(ga.pop))[:n] {
+			out[i] = ga.pop[j].Copy()
+		}
+	case GAEmigrateSelect:
+		for i := range out {
+			out[i] = ga.Parameter.Selector.SelectOne(ga.pop).Copy()
+		}
+	}
+	return out
+}
+
+// immigrate puts the immigrants in the population of the island, which keeps
+// its size and is left sorted.
+func (m *GAMigration) immigrate(ga *GA, immigrants GAGenomes) {
+	sort.Sort(immigrants)
+	if len(immigrants) > len(ga.pop) {
+		immigrants = immigrants[:len(ga.pop)]
+	}
+	sort.Sort(ga.pop)
+	switch m.Immigrants {
+	case GAImmigrateWorst:
+		copy(ga.pop[len(ga.pop)-len(immigrants):], immigrants)
+	case GAImmigrateRandom:
+		if len(ga.pop) > 1 {
+			if len(immigrants) > len(ga.pop)-1 {
+				immigrants = immigrants[:len(ga.pop)-1]
+			}
+			for i, j := range rng.Perm(len(ga.pop) - 1)[:len(immigrants)] {
+				ga.pop[j+1] = immigrants[i]
+			}
+		}
+	case GAImmigrateBetter:
+		// Best immigrants against worst residents.
+		for i, j := 0, len(ga.pop)-1; i < len(immigrants) && immigrants[i].Score() < ga.pop[j].Score(); i, j = i+1, j-1 {
+			ga.pop[j] = immigrants[i]
+		}
+	}
+	sort.Sort(ga.pop)
+}
+
+// migrate moves emigrants between the islands along the topology. All
+// emigrants are picked before any island receives immigrants.
+func (m *GAMigration) migrate(islands []*GA) {
+	if m.Topology == nil || m.Size <= 0 || len(islands) < 2 {
+		return
+	}
+	immigrants := make([]GAGenomes, len(islands))
+	for i, ga := range islands {
+		for _, j := range m.Topology.Targets(i, len(islands)) {
+			emigrants := m.emigrants(ga)
+			if m.Convert != nil {
+				for k, g := range emigrants {
+					emigrants[k] = m.Convert(g, i, j)
+				}
+			}
+			immigrants[j] = AppendGenomes(immigrants[j], emigrants)
+		}
+	}
+	for i, ga := range islands {
+		if len(immigrants[i]) > 0 {
+			m.immigrate(ga, immigrants[i])
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package ga
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopologies(t *testing.T) {
+	tests := []struct {
+		t    GATopology
+		i, n int
+		want []int
+	}{
+		{new(GARingTopology), 3, 4, []int{0}},
+		{new(GARingTopology), 1, 4, []int{2}},
+		{&GAStarTopology{Hub: 1}, 1, 4, []int{0, 2, 3}},
+		{&GAStarTopology{Hub: 1}, 3, 4, []int{1}},
+		{new(GAFullTopology), 2, 4, []int{0, 1, 3}},
+		{new(GAHypercubeTopology), 5, 8, []int{4, 7, 1}},
+		{new(GAHypercubeTopology), 2, 5, []int{3, 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.t.Targets(tt.i, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s.Targets(%d, %d) = %v; want %v", tt.t, tt.i, tt.n, got, tt.want)
+		}
+	}
+	for k := 0; k < 100; k++ {
+		seen := make(map[int]bool)
+		for _, j := range (&GARandomTopology{Degree: 3}).Targets(2, 5) {
+			if j == 2 || j < 0 || j >= 5 || seen[j] {
+				t.Fatalf("GARandomTopology.Targets(2, 5) sent to %d twice or to itself", j)
+			}
+			seen[j] = true
+		}
+		if len(seen) != 3 {
+			t.Fatalf("GARandomTopology{Degree: 3} sent to %d islands; want 3", len(seen))
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("GAStarTopology with hub 4 of 4 islands did not panic")
+		}
+	}()
+	(&GAStarTopology{Hub: 4}).Targets(0, 4)
+}
+
+// islands returns n islands holding the int genomes 10*i..10*i+9.
+func islands(n int) []*GA {
+	gas := make([]*GA, n)
+	for i := range gas {
+		gas[i] = NewGA(GAParameter{})
+		gas[i].pop = intGenomes(10*i, 10*i+1, 10*i+2, 10*i+3, 10*i+4, 10*i+5, 10*i+6, 10*i+7, 10*i+8, 10*i+9)
+		gas[i].popsize = 10
+	}
+	return gas
+}
+
+func TestMigration(t *testing.T) {
+	gas := islands(3)
+	m := GAMigration{Topology: new(GARingTopology), Interval: 1, Size: 2}
+	m.migrate(gas)
+	for i, ga := range gas {
+		src := (i + 2) % 3
+		if got, want := firstGenes(ga.pop), firstGenesOf(i, src); !reflect.DeepEqual(got, want) {
+			t.Errorf("island %d after migration = %v; want %v", i, got, want)
+		}
+	}
+
+	// Only better immigrants take the place of residents.
+	gas = islands(2)
+	m = GAMigration{Topology: new(GAFullTopology), Size: 3, Immigrants: GAImmigrateBetter}
+	m.migrate(gas)
+	if got := firstGenes(gas[1].pop); !reflect.DeepEqual(got, []int{0, 1, 2, 10, 11, 12, 13, 14, 15, 16}) {
+		t.Errorf("island 1 after migration = %v; want the best of 0 in place of the worst", got)
+	}
+	if got := firstGenes(gas[0].pop); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("island 0 after migration = %v; want no change", got)
+	}
+
+	gas = islands(2)
+	m = GAMigration{Topology: new(GARingTopology), Size: 4, Emigrants: GAEmigrateRandom, Immigrants: GAImmigrateRandom}
+	m.migrate(gas)
+	g := firstGenes(gas[1].pop)
+	kept := false
+	for _, x := range g {
+		kept = kept || x == 10
+	}
+	if len(g) != 10 || !kept {
+		t.Errorf("island 1 after random migration = %v; want 10 genomes keeping its best", g)
+	}
+}
+
+func firstGenes(pop GAGenomes) []int {
+	genes := make([]int, len(pop))
+	for i, g := range pop {
+		genes[i] = g.(*GAIntGenome).Gene[0]
+	}
+	return genes
+}
+
+// firstGenesOf returns the sorted genes of island i of islands after it
+// received the best 2 genomes of island src in place of its worst.
+func firstGenesOf(i, src int) []int {
+	genes := []int{10 * src, 10*src + 1}
+	for k := 0; k < 8; k++ {
+		genes = append(genes, 10*i+k)
+	}
+	if src > i {
+		genes = append(genes[2:], genes[:2]...)
+	}
+	return genes
+}
+
+// countTopology counts the migrations along a ring.
+type countTopology struct {
+	GARingTopology
+	calls int
+}
+
+func (t *countTopology) Targets(i, n int) []int {
+	t.calls++
+	return t.GARingTopology.Targets(i, n)
+}
+
+func TestGAParallelMigrationInterval(t *testing.T) {
+	param := GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Mutator:     NewGAGaussianMutator(1, 0),
+		PMutate:     0.5}
+	gap := NewGAParallel(param, 4)
+	top := new(countTopology)
+	gap.Migration = GAMigration{Topology: top, Interval: 3, Size: 1}
+	gap.Init(10, NewFloatGenome(make([]float64, 3), sphere, 10, -10))
+	for _, step := range []struct{ gen, generation, migrations int }{
+		{2, 2, 0}, {1, 3, 1}, {4, 7, 2}, {6, 13, 4},
+	} {
+		gap.Optimize(step.gen)
+		if m := top.calls / 4; m != step.migrations {
+			t.Errorf("%d migrations after %d generations; want %d", m, step.generation, step.migrations)
+		}
+		for i, ga := range gap.ga {
+			if ga.Generation() != step.generation || len(ga.pop) != 10 {
+				t.Errorf("island %d at generation %d with %d genomes; want %d and 10", i, ga.Generation(), len(ga.pop), step.generation)
+			}
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+MOEA/D of Zhang and Li. The multi-objective problem is decomposed into one
+single-objective subproblem for each weight vector, each with one genome.
+Children are made from the genomes of neighbouring subproblems and replace
+neighbours they solve better.
+*/
+
+package ga
+
+import (
+	"math"
+	"sort"
+)
+
+// How MOEA/D turns an objective vector into the score of a subproblem.
+type GADecomposition int
+
+const (
+	// Largest weighted distance to the ideal point in any objective.
+	GATchebycheff GADecomposition = iota
+	// Penalty-based boundary intersection: distance to the ideal point
+	// along the weight vector plus Theta times the distance from it.
+	GAPBI
+)
+
+type GAMOEAD struct {
+	// Initializer, Breeder, Mutator, PBreed and PMutate are used as by GA.
+	// Parents are picked among the neighbours, the Selector is not used.
+	Parameter GAParameter
+	// Objective vector of a genome, every objective is minimized. Only used
+	// for genomes that do not implement GAObjectiveGenome.
+	Objectives func(g GAGenome) []float64
+	// Constraint violation of a genome, 0 if it is feasible, nil if there
+	// are no constraints. Only used for genomes that do not implement
+	// GAConstrainedGenome.
+	Violation func(g GAGenome) float64
+	// One weight vector for each subproblem, for example from DasDennis
+	Weights [][]float64
+	// Number of neighbouring subproblems, 0 uses 20
+	Neighbours    int
+	Decomposition GADecomposition
+	// Penalty of GAPBI, 0 uses 5
+	Theta float64
+	// Most neighbours a child replaces, 0 does not limit it
+	Replacements int
+
+	pop        []*gaIndividual
+	neighbours [][]int
+	ideal      []float64
+	generation int
+}
+
+func NewGAMOEAD(parameter GAParameter, objectives func(g GAGenome) []float64, weights [][]float64) *GAMOEAD {
+	return &GAMOEAD{Parameter: parameter, Objectives: objectives, Weights: weights}
+}
+
+func (ga *GAMOEAD) String() string { return "GAMOEAD" }
+
+// Init creates a genome for every weight vector.
+func (ga *GAMOEAD) Init(i GAGenome) {
+	n := len(ga.Weights)
+	if n == 0 {
+		panic("No weight vectors")
+	}
+	t := ga.Neighbours
+	if t <= 0 {
+		t = 20
+	}
+	if t > n {
+		t = n
+	}
+	ga.neighbours = make([][]int, n)
+	for a := range ga.Weights {
+		idx := make([]int, n)
+		d := make([]float64, n)
+		for b := range ga.Weights {
+			idx[b] = b
+			for k := range ga.Weights[a] {
+				x := ga.Weights[a][k] - ga.Weights[b][k]
+				d[b] += x * x
+			}
+		}
+		sort.SliceStable(idx, func(x, y int) bool { return d[idx[x]] < d[idx[y]] })
+		ga.neighbours[a] = idx[:t]
+	}
+	ga.generation = 0
+	ga.pop = individuals(ga.Parameter.Initializer.InitPop(i, n), ga.Objectives, ga.Violation)
+	ga.ideal = nil
+	for _, x := range ga.pop {
+		ga.updateIdeal(x.obj)
+	}
+}
+
+func (ga *GAMOEAD) updateIdeal(f []float64) {
+	if ga.ideal == nil {
+		ga.ideal = append([]float64(nil), f...)
+		return
+	}
+	for k := range f {
+		ga.ideal[k] = math.Min(ga.ideal[k], f[k])
+	}
+}
+
+// scalar returns the score of f for the subproblem with weight vector w.
+func (ga *GAMOEAD) scalar(f, w []float64) float64 {
+	switch ga.Decomposition {
+	case GAPBI:
+		theta := ga.Theta
+		if theta == 0 {
+			theta = 5
+		}
+		var norm, d1 float64
+		for k := range w {
+			norm += w[k] * w[k]
+		}
+		norm = math.Sqrt(norm)
+		for k := range f {
+			d1 += (f[k] - ga.ideal[k]) * w[k] / norm
+		}
+		var d2 float64
+		for k := range f {
+			x := f[k] - ga.ideal[k] - d1*w[k]/norm
+			d2 += x * x
+		}
+		return d1 + theta*math.Sqrt(d2)
+	}
+	g := math.Inf(-1)
+	for k := range f {
+		wk := w[k]
+		if wk == 0 {
+			wk = 1e-6
+		}
+		g = math.Max(g, wk*math.Abs(f[k]-ga.ideal[k]))
+	}
+	return g
+}
+
+// better reports whether a solves the subproblem with weight vector w
+// better than b. Feasible genomes are better than infeasible ones and of
+// two infeasible genomes the one violating the constraints less is better.
+func (ga *GAMOEAD) better(a, b *gaIndividual, w []float64) bool {
+	switch {
+	case a.violation <= 0 && b.violation > 0:
+		return true
+	case a.violation > 0 && b.violation <= 0:
+		return false
+	case a.violation > 0:
+		return a.violation < b.violation
+	}
+	return ga.scalar(a.obj, w) <= ga.scalar(b.obj, w)
+}
+
+func (ga *GAMOEAD) Optimize(gen int) {
+	for i := 0; i < gen; i++ {
+		for s := range ga.pop {
+			b := ga.neighbours[s]
+			p1, p2 := ga.pop[b[rng.Intn(len(b))]], ga.pop[b[rng.Intn(len(b))]]
+			child := individual(offspring(&ga.Parameter, p1.g, p2.g)[0], ga.Objectives, ga.Violation)
+			ga.updateIdeal(child.obj)
+			replaced := 0
+			for _, j := range rng.Perm(len(b)) {
+				if ga.Replacements > 0 && replaced >= ga.Replacements {
+					break
+				}
+				if ga.better(child, ga.pop[b[j]], ga.Weights[b[j]]) {
+					ga.pop[b[j]] = child
+					replaced++
+				}
+			}
+		}
+		ga.generation++
+	}
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GAMOEAD) Generation() int { return ga.generation }
+
+// Population returns the genome of every subproblem, in the order of the
+// weight vectors.
+func (ga *GAMOEAD) Population() GAGenomes { return genomesOf(ga.pop) }
+
+// ParetoFront returns the distinct genomes of the population no other genome
+// of the population dominates.
+func (ga *GAMOEAD) ParetoFront() GAGenomes { return genomesOf(paretoFront(ga.distinct())) }
+
+// ParetoObjectives returns the objective vectors of the ParetoFront, in the
+// same order.
+func (ga *GAMOEAD) ParetoObjectives() [][]float64 {
+	return objectivesOf(paretoFront(ga.distinct()))
+}
+
+// distinct returns the population without the repeats of children that
+// replaced several neighbours.
+func (ga *GAMOEAD) distinct() []*gaIndividual {
+	seen := make(map[*gaIndividual]bool)
+	var d []*gaIndividual
+	for _, x := range ga.pop {
+		if !seen[x] {
+			seen[x] = true
+			d = append(d, x)
+		}
+	}
+	return d
+}
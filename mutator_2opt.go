@@ -0,0 +1,78 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+2-opt move for tours. Removes two edges of the tour and reconnects it
+the other way by reversing the segment between them. Without a distance
+matrix the move is random. With a distance matrix the mutator makes the
+move that shortens the tour the most and only falls back to a random
+move when the tour is already 2-opt optimal.
+*/
+
+package ga
+
+type GA2OptMutator struct {
+	// Dist[a][b] is the distance between the cities a and b, optional.
+	Dist [][]float64
+	// Number of random moves to consider when Dist is set, 0 considers all.
+	Tries int
+}
+
+func NewGA2OptMutator(dist [][]float64, tries int) *GA2OptMutator {
+	return &GA2OptMutator{Dist: dist, Tries: tries}
+}
+
+func (m GA2OptMutator) Mutate(a GAGenome) GAGenome {
+	n := a.Copy()
+	l := a.Len()
+	if l < 4 {
+		return GAInversionMutator{}.Mutate(a)
+	}
+	if o, ok := n.(*GAOrderedIntGenome); ok && m.Dist != nil {
+		if i, j, found := m.best(o.Gene); found {
+			reverse(n, i+1, j)
+			return n
+		}
+	}
+	i := rng.Intn(l - 2)
+	j := i + 2 + rng.Intn(l-i-2)
+	reverse(n, i+1, j)
+	return n
+}
+
+// delta returns the change in tour length when reversing tour[i+1:j+1].
+func (m GA2OptMutator) delta(tour []int, i, j int) float64 {
+	a, b := tour[i], tour[i+1]
+	c, d := tour[j], tour[(j+1)%len(tour)]
+	return m.Dist[a][c] + m.Dist[b][d] - m.Dist[a][b] - m.Dist[c][d]
+}
+
+// best returns the most improving move, found is false if no move improves.
+func (m GA2OptMutator) best(tour []int) (bi, bj int, found bool) {
+	l := len(tour)
+	min := 0.0
+	try := func(i, j int) {
+		if i == 0 && j == l-1 {
+			// Both edges touch the same city, not a move.
+			return
+		}
+		if d := m.delta(tour, i, j); d < min {
+			min, bi, bj, found = d, i, j, true
+		}
+	}
+	if m.Tries > 0 {
+		for t := 0; t < m.Tries; t++ {
+			i := rng.Intn(l - 2)
+			try(i, i+2+rng.Intn(l-i-2))
+		}
+		return
+	}
+	for i := 0; i < l-2; i++ {
+		for j := i + 2; j < l; j++ {
+			try(i, j)
+		}
+	}
+	return
+}
+
+func (m GA2OptMutator) String() string { return "GA2OptMutator" }
@@ -0,0 +1,100 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Bit flip mutators for GAFixedBitstringGenome.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+// Flips each bit of the genome independently with chance PFlip. A PFlip of 0
+// means 1/L where L is the length of the genome. Instead of drawing one random
+// number per bit the mutator draws the distance to the next flipped bit from a
+// geometric distribution, so the cost is proportional to the number of flips
+// rather than to the length of the genome.
+type GABitFlipMutator struct {
+	PFlip float64
+}
+
+func NewGABitFlipMutator(pflip float64) *GABitFlipMutator {
+	return &GABitFlipMutator{PFlip: pflip}
+}
+
+func (m GABitFlipMutator) Mutate(a GAGenome) GAGenome {
+	n := bitstring(a).Copy().(*GAFixedBitstringGenome)
+	l := len(n.Gene)
+	if l == 0 {
+		return n
+	}
+	p := m.PFlip
+	if p == 0 {
+		p = 1 / float64(l)
+	}
+	switch {
+	case p >= 1:
+		for i := range n.Gene {
+			n.Gene[i] = !n.Gene[i]
+		}
+	case p > 0:
+		lq := math.Log1p(-p)
+		for i := skip(lq); i < l; i += 1 + skip(lq) {
+			n.Gene[i] = !n.Gene[i]
+		}
+	}
+	n.Reset()
+	return n
+}
+func (m GABitFlipMutator) String() string { return "GABitFlipMutator" }
+
+// skip returns the number of bits to pass over before the next flip, a
+// geometric variate where lq is log(1-p).
+func skip(lq float64) int {
+	s := math.Floor(math.Log(1-rng.Float64()) / lq)
+	if s > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(s)
+}
+
+// Flips exactly K distinct random bits of the genome.
+type GAKFlipMutator struct {
+	K int
+}
+
+func NewGAKFlipMutator(k int) *GAKFlipMutator {
+	return &GAKFlipMutator{K: k}
+}
+
+func (m GAKFlipMutator) Mutate(a GAGenome) GAGenome {
+	n := bitstring(a).Copy().(*GAFixedBitstringGenome)
+	l := len(n.Gene)
+	k := m.K
+	if k > l {
+		k = l
+	}
+	// Floyd's algorithm for sampling k distinct positions.
+	picked := make(map[int]bool, k)
+	for j := l - k; j < l; j++ {
+		i := rng.Intn(j + 1)
+		if picked[i] {
+			i = j
+		}
+		picked[i] = true
+		n.Gene[i] = !n.Gene[i]
+	}
+	n.Reset()
+	return n
+}
+func (m GAKFlipMutator) String() string { return "GAKFlipMutator" }
+
+func bitstring(a GAGenome) *GAFixedBitstringGenome {
+	b, ok := a.(*GAFixedBitstringGenome)
+	if !ok {
+		panic("Bit flip mutator needs a GAFixedBitstringGenome")
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func countTrue(gene []bool) int {
+	c := 0
+	for _, b := range gene {
+		if b {
+			c++
+		}
+	}
+	return c
+}
+
+// Tests that the average number of flipped bits matches PFlip * L and that
+// every position gets flipped about equally often.
+func TestBitFlipMutatorRate(t *testing.T) {
+	tests := []struct {
+		length int
+		pflip  float64
+		iters  int
+	}{
+		{100, 0, 100000},
+		{100, 0.05, 20000},
+		{10000, 0.001, 2000},
+		{20, 0.5, 20000},
+	}
+	for _, test := range tests {
+		m := NewGABitFlipMutator(test.pflip)
+		g := NewFixedBitstringGenome(make([]bool, test.length), nil)
+		count := make([]int, test.length)
+		total := 0
+		for i := 0; i < test.iters; i++ {
+			c := m.Mutate(g).(*GAFixedBitstringGenome)
+			for j, b := range c.Gene {
+				if b {
+					count[j]++
+					total++
+				}
+			}
+		}
+		p := test.pflip
+		if p == 0 {
+			p = 1 / float64(test.length)
+		}
+		want := p * float64(test.length)
+		got := float64(total) / float64(test.iters)
+		if math.Abs(got-want)/want > 0.05 {
+			t.Errorf("BitFlip(L=%v, p=%v) flipped %v bits on average; want %v",
+				test.length, test.pflip, got, want)
+		}
+		// Compare the first and last quarter to catch positional bias.
+		q := test.length / 4
+		first, last := 0, 0
+		for j := 0; j < q; j++ {
+			first += count[j]
+			last += count[test.length-1-j]
+		}
+		if s := float64(first+1) / float64(last+1); s < 0.9 || s > 1.1 {
+			t.Errorf("BitFlip(L=%v, p=%v) positional bias %v; want ~1", test.length, test.pflip, s)
+		}
+	}
+}
+
+func TestBitFlipMutatorAll(t *testing.T) {
+	g := NewFixedBitstringGenome(make([]bool, 10), nil)
+	c := NewGABitFlipMutator(1).Mutate(g).(*GAFixedBitstringGenome)
+	if n := countTrue(c.Gene); n != 10 {
+		t.Errorf("BitFlip(p=1) flipped %d bits; want 10", n)
+	}
+	if n := countTrue(g.Gene); n != 0 {
+		t.Errorf("BitFlip modified its parent: %v", g.Gene)
+	}
+}
+
+func TestKFlipMutator(t *testing.T) {
+	for _, k := range []int{0, 1, 5, 50, 64, 100} {
+		m := NewGAKFlipMutator(k)
+		g := NewFixedBitstringGenome(make([]bool, 64), nil)
+		for i := 0; i < 100; i++ {
+			c := m.Mutate(g).(*GAFixedBitstringGenome)
+			want := k
+			if want > 64 {
+				want = 64
+			}
+			if n := countTrue(c.Gene); n != want {
+				t.Fatalf("KFlip(k=%d) flipped %d bits; want %d", k, n, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Cauchy mutation. Like GAGaussianMutator but the heavy tails of the Cauchy
+distribution make occasional long jumps likely, which helps to escape
+local optima.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+type GACauchyMutator struct {
+	// Scale of the Cauchy distribution (half width at half maximum).
+	Scale float64
+	// Chance of each gene being mutated, 0 means 1/n.
+	PGene float64
+	// Handling of genes that leave the range of the genome.
+	Bound GABoundHandling
+}
+
+func NewGACauchyMutator(scale, pgene float64) *GACauchyMutator {
+	if scale == 0 {
+		return nil
+	}
+	return &GACauchyMutator{Scale: scale, PGene: pgene}
+}
+
+func (m GACauchyMutator) Mutate(a GAGenome) GAGenome {
+	return mutateReal(a, m.PGene, m.Bound, func(x, min, max float64) float64 {
+		return x + m.Scale*math.Tan(math.Pi*(rng.Float64()-0.5))
+	})
+}
+func (m GACauchyMutator) String() string { return "GACauchyMutator" }
@@ -0,0 +1,39 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+This mutator copies the genome, cuts a random segment out of the
+copy and reinserts it at a random position. Works on any genome
+and keeps permutations valid.
+*/
+
+package ga
+
+type GADisplacementMutator struct{}
+
+func (m GADisplacementMutator) Mutate(a GAGenome) GAGenome {
+	n := a.Copy()
+	l := a.Len()
+	if l < 2 {
+		return n
+	}
+	length := rng.Intn(l-1) + 1
+	from := rng.Intn(l - length + 1)
+	to := rng.Intn(l - length + 1)
+	displace(n, a, from, to, length)
+	return n
+}
+func (m GADisplacementMutator) String() string { return "GADisplacementMutator" }
+
+// displace moves the segment a[from:from+length] so that it starts at to in
+// n, shifting the genes in between to close the gap. n must be a copy of a.
+func displace(n, a GAGenome, from, to, length int) {
+	switch {
+	case to < from:
+		n.Splice(a, from, to, length)
+		n.Splice(a, to, to+length, from-to)
+	case to > from:
+		n.Splice(a, from+length, from, to-from)
+		n.Splice(a, from, to, length)
+	}
+}
@@ -8,10 +8,6 @@ Shifts the whole genome random length to the right.
 
 package ga
 
-import (
-	"math/rand"
-)
-
 type GAGaussianMutator struct {
 	StdDev float64
 	Mean   float64
@@ -29,15 +25,19 @@ func (m GAGaussianMutator) Mutate(a GAGenome) GAGenome {
 	case *GAFloatGenome:
 		n := a.Copy().(*GAFloatGenome)
 		l := a.Len()
-		s := rand.Intn(l)
-		n.Gene[s] += rand.NormFloat64()*m.StdDev + m.Mean
+		s := rng.Intn(l)
+		n.Gene[s] += rng.NormFloat64()*m.StdDev + m.Mean
+		n.Reset()
 		return n
 	case *GAFloat32Genome:
 		n := a.Copy().(*GAFloat32Genome)
 		l := a.Len()
-		s := rand.Intn(l)
-		n.Gene[s] += float32(rand.NormFloat64()*m.StdDev + m.Mean)
+		s := rng.Intn(l)
+		n.Gene[s] += float32(rng.NormFloat64()*m.StdDev + m.Mean)
+		n.Reset()
 		return n
+	case *GAIntGenome:
+		return GAIntGaussianMutator{StdDev: m.StdDev, Mean: m.Mean}.Mutate(a)
 	}
 
 	return nil
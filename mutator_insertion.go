@@ -0,0 +1,23 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+This mutator copies the genome, removes one random gene from the
+copy and inserts it at another random position. Works on any genome
+and keeps permutations valid.
+*/
+
+package ga
+
+type GAInsertionMutator struct{}
+
+func (m GAInsertionMutator) Mutate(a GAGenome) GAGenome {
+	n := a.Copy()
+	l := a.Len()
+	if l < 2 {
+		return n
+	}
+	displace(n, a, rng.Intn(l), rng.Intn(l), 1)
+	return n
+}
+func (m GAInsertionMutator) String() string { return "GAInsertionMutator" }
@@ -0,0 +1,98 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Mutators for GAIntGenome that respect the [Min,Max] range of the genome.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+// Adds or subtracts a random amount between 1 and Step to one random gene.
+// Results outside the range of the genome are clamped.
+type GACreepMutator struct {
+	Step int
+}
+
+func NewGACreepMutator(step int) *GACreepMutator {
+	if step < 1 {
+		return nil
+	}
+	return &GACreepMutator{Step: step}
+}
+
+func (m GACreepMutator) Mutate(a GAGenome) GAGenome {
+	n := intgenome(a).Copy().(*GAIntGenome)
+	if len(n.Gene) == 0 {
+		return n
+	}
+	s := rng.Intn(len(n.Gene))
+	d := rng.Intn(m.Step) + 1
+	if rng.Intn(2) == 0 {
+		d = -d
+	}
+	n.Gene[s] = n.clamp(n.Gene[s] + d)
+	n.Reset()
+	return n
+}
+func (m GACreepMutator) String() string { return "GACreepMutator" }
+
+// Replaces one random gene with a uniformly chosen value in [Min,Max].
+type GARandomResetMutator struct{}
+
+func (m GARandomResetMutator) Mutate(a GAGenome) GAGenome {
+	n := intgenome(a).Copy().(*GAIntGenome)
+	if len(n.Gene) == 0 {
+		return n
+	}
+	s := rng.Intn(len(n.Gene))
+	n.RandomizeGene(s)
+	return n
+}
+func (m GARandomResetMutator) String() string { return "GARandomResetMutator" }
+
+// Adds a normally distributed value, rounded to the nearest integer, to one
+// random gene. Results outside the range of the genome are clamped.
+type GAIntGaussianMutator struct {
+	StdDev float64
+	Mean   float64
+}
+
+func NewGAIntGaussianMutator(stddev float64, mean float64) *GAIntGaussianMutator {
+	if stddev == 0 {
+		return nil
+	}
+	return &GAIntGaussianMutator{StdDev: stddev, Mean: mean}
+}
+
+func (m GAIntGaussianMutator) Mutate(a GAGenome) GAGenome {
+	n := intgenome(a).Copy().(*GAIntGenome)
+	if len(n.Gene) == 0 {
+		return n
+	}
+	s := rng.Intn(len(n.Gene))
+	d := math.Floor(rng.NormFloat64()*m.StdDev + m.Mean + 0.5)
+	v := float64(n.Gene[s]) + d
+	switch {
+	case v < float64(n.min):
+		n.Gene[s] = n.min
+	case v > float64(n.max):
+		n.Gene[s] = n.max
+	default:
+		n.Gene[s] = int(v)
+	}
+	n.Reset()
+	return n
+}
+func (m GAIntGaussianMutator) String() string { return "GAIntGaussianMutator" }
+
+func intgenome(a GAGenome) *GAIntGenome {
+	g, ok := a.(*GAIntGenome)
+	if !ok {
+		panic("Integer mutator needs a GAIntGenome")
+	}
+	return g
+}
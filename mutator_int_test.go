@@ -0,0 +1,54 @@
+package ga
+
+import (
+	"testing"
+)
+
+// Tests that the integer mutators change at most one gene and never leave the
+// range of the genome.
+func TestIntMutatorsBounds(t *testing.T) {
+	mutators := []GAMutator{
+		NewGACreepMutator(3),
+		GARandomResetMutator{},
+		NewGAIntGaussianMutator(5, 0),
+		NewGAGaussianMutator(5, 0),
+	}
+	for _, m := range mutators {
+		g := NewIntGenome([]int{-2, 0, 2, -2, 2}, nil, -2, 2)
+		for i := 0; i < 10000; i++ {
+			c := m.Mutate(g).(*GAIntGenome)
+			changed := 0
+			for j, v := range c.Gene {
+				if v < g.Min() || v > g.Max() {
+					t.Fatalf("%s.Mutate(%v) = %v; want genes in [%d,%d]",
+						m, g.Gene, c.Gene, g.Min(), g.Max())
+				}
+				if v != g.Gene[j] {
+					changed++
+				}
+			}
+			if changed > 1 {
+				t.Fatalf("%s.Mutate(%v) = %v; want at most one changed gene", m, g.Gene, c.Gene)
+			}
+		}
+	}
+}
+
+// Tests that the creep mutator moves a gene by at most Step.
+func TestCreepMutatorStep(t *testing.T) {
+	m := NewGACreepMutator(2)
+	g := NewIntGenome([]int{50}, nil, 0, 100)
+	seen := make(map[int]bool)
+	for i := 0; i < 10000; i++ {
+		c := m.Mutate(g).(*GAIntGenome)
+		seen[c.Gene[0]-50] = true
+	}
+	for _, d := range []int{-2, -1, 1, 2} {
+		if !seen[d] {
+			t.Errorf("GACreepMutator(2) never moved a gene by %d", d)
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("GACreepMutator(2) moved genes by %v; want -2, -1, 1, 2", seen)
+	}
+}
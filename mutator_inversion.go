@@ -0,0 +1,36 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+This mutator copies the genome and reverses a random segment of
+the copy. Works on any genome and keeps permutations valid.
+*/
+
+package ga
+
+type GAInversionMutator struct{}
+
+func (m GAInversionMutator) Mutate(a GAGenome) GAGenome {
+	n := a.Copy()
+	l := a.Len()
+	if l < 2 {
+		return n
+	}
+	p1 := rng.Intn(l)
+	p2 := rng.Intn(l)
+	if p1 > p2 {
+		p1, p2 = p2, p1
+	}
+	reverse(n, p1, p2)
+	return n
+}
+func (m GAInversionMutator) String() string { return "GAInversionMutator" }
+
+// reverse reverses genes p1 to p2 inclusive in place.
+func reverse(g GAGenome, p1, p2 int) {
+	for p1 < p2 {
+		g.Switch(p1, p2)
+		p1++
+		p2--
+	}
+}
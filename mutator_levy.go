@@ -0,0 +1,45 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Lévy flight mutation. Steps are drawn from a Lévy stable distribution
+using Mantegna's algorithm, mostly short steps with rare very long ones.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+type GALevyMutator struct {
+	// Stability index in (0,2], 1.5 is common. Smaller values give
+	// heavier tails.
+	Beta float64
+	// Step size multiplier.
+	Scale float64
+	// Chance of each gene being mutated, 0 means 1/n.
+	PGene float64
+	// Handling of genes that leave the range of the genome.
+	Bound GABoundHandling
+}
+
+func NewGALevyMutator(beta, scale, pgene float64) *GALevyMutator {
+	if beta <= 0 || beta > 2 || scale == 0 {
+		return nil
+	}
+	return &GALevyMutator{Beta: beta, Scale: scale, PGene: pgene}
+}
+
+func (m GALevyMutator) Mutate(a GAGenome) GAGenome {
+	b := m.Beta
+	gu, _ := math.Lgamma(1 + b)
+	gd, _ := math.Lgamma((1 + b) / 2)
+	su := math.Pow(math.Exp(gu-gd)*math.Sin(math.Pi*b/2)/(b*math.Pow(2, (b-1)/2)), 1/b)
+	return mutateReal(a, m.PGene, m.Bound, func(x, min, max float64) float64 {
+		u := rng.NormFloat64() * su
+		v := math.Abs(rng.NormFloat64())
+		return x + m.Scale*u/math.Pow(v, 1/b)
+	})
+}
+func (m GALevyMutator) String() string { return "GALevyMutator" }
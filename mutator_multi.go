@@ -3,46 +3,119 @@ Copyright 2009 Thomas Jager <mail@jager.no> All rights reserved.
 Use of this source code is governed by a BSD-style
 license that can be found in the LICENSE file.
 
-Combines several mutators into one, each mutation has equal chance of occuring.
+Combines several mutators into one. Each mutation has equal chance of occuring
+unless the mutators are given weights.
 */
 
 package ga
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
 	"fmt"
-	"math/rand"
 	"strings"
 )
 
 type GAMultiMutator struct {
-	v     []GAMutator
-	stats []int
+	v       []GAMutator
+	weights []float64
+	total   float64
+	stats   []int
 }
 
 // NewMultiMutator returns a new, empty, multi mutator.
 func NewMultiMutator() *GAMultiMutator {
 	return &GAMultiMutator{
-		v:     make([]GAMutator, 0),
-		stats: make([]int, 0),
+		v:       make([]GAMutator, 0),
+		weights: make([]float64, 0),
+		stats:   make([]int, 0),
 	}
 }
 
-// Mutate mutates the genome using one of the mutators added using Add(). Each
-// mutator has equal chance of being chosen.
+// Mutate mutates the genome using one of the mutators added using Add() or
+// AddWeighted(). Each mutator is chosen with a chance proportional to its
+// weight.
 func (m *GAMultiMutator) Mutate(a GAGenome) GAGenome {
-	if len(m.v) == 0 {
+	if len(m.v) == 0 || m.total <= 0 {
 		// No mutators, so nothing to do.
 		return a.Copy()
 	}
-	r := rand.Intn(len(m.v))
+	r := m.pick()
 	m.stats[r]++
 	return m.v[r].Mutate(a)
 }
 
-// Add adds a mutator to the MultiMutator.
+// pick returns the index of a mutator chosen by roulette wheel on the weights.
+func (m *GAMultiMutator) pick() int {
+	x := rng.Float64() * m.total
+	for i, w := range m.weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	// Rounding may leave x just above the last weight, fall back to the last
+	// mutator that can be chosen.
+	for i := len(m.weights) - 1; i > 0; i-- {
+		if m.weights[i] > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// Add adds a mutator to the MultiMutator with weight 1.
 func (m *GAMultiMutator) Add(a GAMutator) {
+	m.AddWeighted(a, 1)
+}
+
+// AddWeighted adds a mutator to the MultiMutator with the given weight. A
+// mutator with weight 2 is chosen twice as often as one with weight 1.
+func (m *GAMultiMutator) AddWeighted(a GAMutator, weight float64) {
+	if weight < 0 {
+		panic("Negative mutator weight")
+	}
 	m.v = append(m.v, a)
+	m.weights = append(m.weights, weight)
 	m.stats = append(m.stats, 0)
+	m.total += weight
+}
+
+// SetWeight changes the weight of the i'th added mutator. It can be called
+// between generations to shift the bias of the MultiMutator during a run.
+func (m *GAMultiMutator) SetWeight(i int, weight float64) {
+	if weight < 0 {
+		panic("Negative mutator weight")
+	}
+	m.weights[i] = weight
+	m.total = 0
+	for _, w := range m.weights {
+		m.total += w
+	}
+}
+
+// Weight returns the weight of the i'th added mutator.
+func (m *GAMultiMutator) Weight(i int) float64 { return m.weights[i] }
+
+// Clone returns a copy of the MultiMutator with its own weights and usage
+// counts, for the islands of a GAParallel. Mutators implementing GACloner are
+// cloned as well.
+func (m *GAMultiMutator) Clone() interface{} {
+	c := &GAMultiMutator{
+		v:       make([]GAMutator, len(m.v)),
+		weights: append([]float64(nil), m.weights...),
+		total:   m.total,
+		stats:   append([]int(nil), m.stats...),
+	}
+	for i, a := range m.v {
+		if cl, ok := a.(GACloner); ok {
+			a = cl.Clone().(GAMutator)
+		}
+		c.v[i] = a
+	}
+	return c
 }
 
 // String returns the name of the mutator.
@@ -56,3 +129,56 @@ func (m *GAMultiMutator) Stats() string {
 	}
 	return "Used " + strings.Join(o, ", ")
 }
+
+type gaMultiMutatorState struct {
+	Weights []float64
+	Stats   []int
+	// State of the mutators implementing encoding.BinaryMarshaler
+	Mutators [][]byte
+}
+
+// MarshalBinary saves the weights, usage counts and the state of the added
+// mutators, for checkpoints.
+func (m *GAMultiMutator) MarshalBinary() ([]byte, error) {
+	st := gaMultiMutatorState{Weights: m.weights, Stats: m.stats}
+	for _, a := range m.v {
+		var b []byte
+		if ma, ok := a.(encoding.BinaryMarshaler); ok {
+			var err error
+			if b, err = ma.MarshalBinary(); err != nil {
+				return nil, err
+			}
+		}
+		st.Mutators = append(st.Mutators, b)
+	}
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(st)
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary restores the weights, usage counts and the state of the
+// added mutators saved by MarshalBinary. The same mutators must have been
+// added.
+func (m *GAMultiMutator) UnmarshalBinary(data []byte) error {
+	var st gaMultiMutatorState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st); err != nil {
+		return err
+	}
+	if len(st.Weights) != len(m.v) || len(st.Stats) != len(m.v) ||
+		st.Mutators != nil && len(st.Mutators) != len(m.v) {
+		return errors.New("ga: GAMultiMutator state does not match its mutators")
+	}
+	for i, b := range st.Mutators {
+		if u, ok := m.v[i].(encoding.BinaryUnmarshaler); ok && b != nil {
+			if err := u.UnmarshalBinary(b); err != nil {
+				return err
+			}
+		}
+	}
+	m.weights, m.stats = st.Weights, st.Stats
+	m.total = 0
+	for _, w := range m.weights {
+		m.total += w
+	}
+	return nil
+}
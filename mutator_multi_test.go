@@ -79,3 +79,59 @@ func TestMultiMutatorEqualProbability(t *testing.T) {
 		}
 	}
 }
+
+// Tests that each mutator in a MultiMutator is called approximately in
+// proportion to its weight.
+func TestMultiMutatorWeightedProbability(t *testing.T) {
+	tests := []struct {
+		weights   []float64 // Weight of each mutator.
+		iters     int64     // Number of time Mutate() is called.
+		threshold float64   // Minimum allowed similarity between normalized call counts.
+	}{
+		{[]float64{1, 2}, 100000, 0.95},
+		{[]float64{1, 1, 4}, 100000, 0.95},
+		{[]float64{0.5, 1, 2, 4, 8}, 1000000, 0.90},
+	}
+	for _, test := range tests {
+		mm := NewMultiMutator()
+		var mutators testMutators
+		for _, w := range test.weights {
+			tm := testMutator(0)
+			mutators = append(mutators, &tm)
+			mm.AddWeighted(&tm, w)
+		}
+		for i := int64(0); i < test.iters; i++ {
+			mm.Mutate(nil)
+		}
+		// Divide each counter by its weight; the results should be similar.
+		normalized := make(testMutators, len(mutators))
+		for i, m := range mutators {
+			n := testMutator(float64(*m) / test.weights[i])
+			normalized[i] = &n
+		}
+		if got := similarity(t, normalized); got < test.threshold {
+			t.Errorf("Similarity(%v, %v) = %v; want >= %v",
+				test.weights, test.iters, got, test.threshold)
+			t.Errorf("Mutator counters: [%s]", mutators)
+		}
+	}
+}
+
+// Tests that SetWeight changes the distribution, and that a mutator with zero
+// weight is never chosen.
+func TestMultiMutatorSetWeight(t *testing.T) {
+	mm := NewMultiMutator()
+	a, b := testMutator(0), testMutator(0)
+	mm.Add(&a)
+	mm.Add(&b)
+	mm.SetWeight(0, 0)
+	for i := 0; i < 10000; i++ {
+		mm.Mutate(nil)
+	}
+	if a != 0 || b != 10000 {
+		t.Errorf("Mutator counters after SetWeight(0, 0) = [%v, %v]; want [0, 10000]", a, b)
+	}
+	if w := mm.Weight(0); w != 0 {
+		t.Errorf("Weight(0) = %v; want 0", w)
+	}
+}
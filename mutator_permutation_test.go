@@ -0,0 +1,114 @@
+package ga
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// isPermutation reports whether gene holds each of 0..len(gene)-1 exactly once.
+func isPermutation(gene []int) bool {
+	s := append([]int(nil), gene...)
+	sort.Ints(s)
+	for i, c := range s {
+		if c != i {
+			return false
+		}
+	}
+	return true
+}
+
+func identity(n int) []int {
+	gene := make([]int, n)
+	for i := range gene {
+		gene[i] = i
+	}
+	return gene
+}
+
+// Tests that the permutation mutators never produce an invalid permutation and
+// leave the parent untouched.
+func TestPermutationMutatorsValid(t *testing.T) {
+	mutators := []GAMutator{
+		GAInversionMutator{},
+		GAScrambleMutator{},
+		GAInsertionMutator{},
+		GADisplacementMutator{},
+		GA2OptMutator{},
+		NewGA2OptMutator(circle(12), 0),
+		NewGA2OptMutator(circle(12), 5),
+	}
+	for _, m := range mutators {
+		for _, n := range []int{1, 2, 3, 4, 12} {
+			g := NewOrderedIntGenome(identity(n), nil)
+			for i := 0; i < 1000; i++ {
+				c := m.Mutate(g).(*GAOrderedIntGenome)
+				if !isPermutation(c.Gene) {
+					t.Fatalf("%s.Mutate(%v) = %v; want a permutation", m, g.Gene, c.Gene)
+				}
+				if !reflect.DeepEqual(g.Gene, identity(n)) {
+					t.Fatalf("%s.Mutate modified its parent: %v", m, g.Gene)
+				}
+			}
+		}
+	}
+}
+
+func TestDisplace(t *testing.T) {
+	tests := []struct {
+		from, to, length int
+		want             []int
+	}{
+		{1, 4, 2, []int{0, 3, 4, 5, 1, 2}},
+		{4, 1, 2, []int{0, 4, 5, 1, 2, 3}},
+		{0, 5, 1, []int{1, 2, 3, 4, 5, 0}},
+		{2, 2, 3, []int{0, 1, 2, 3, 4, 5}},
+	}
+	for _, test := range tests {
+		a := NewOrderedIntGenome(identity(6), nil)
+		n := a.Copy()
+		displace(n, a, test.from, test.to, test.length)
+		if got := n.(*GAOrderedIntGenome).Gene; !reflect.DeepEqual(got, test.want) {
+			t.Errorf("displace(%v, %v, %v) = %v; want %v",
+				test.from, test.to, test.length, got, test.want)
+		}
+	}
+}
+
+// circle returns the distance matrix of n cities evenly spaced on a circle.
+func circle(n int) [][]float64 {
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			a := 2 * math.Pi * float64(i-j) / float64(n)
+			dist[i][j] = math.Abs(2 * math.Sin(a/2))
+		}
+	}
+	return dist
+}
+
+// Tests that a greedy 2-opt mutator untangles a scrambled tour on a circle,
+// where the optimal tour visits the cities in order.
+func Test2OptMutatorImproves(t *testing.T) {
+	dist := circle(10)
+	length := func(g *GAOrderedIntGenome) float64 {
+		var s float64
+		for i := range g.Gene {
+			s += dist[g.Gene[i]][g.Gene[(i+1)%len(g.Gene)]]
+		}
+		return s
+	}
+	g := NewOrderedIntGenome([]int{0, 5, 2, 7, 4, 9, 6, 1, 8, 3}, length)
+	m := NewGA2OptMutator(dist, 0)
+	for i := 0; i < 100; i++ {
+		g = m.Mutate(g).(*GAOrderedIntGenome)
+		if _, _, found := m.best(g.Gene); !found {
+			break
+		}
+	}
+	if got, want := g.Score(), length(NewOrderedIntGenome(identity(10), nil)); got > want+1e-9 {
+		t.Errorf("2-opt tour length = %v (%v); want %v", got, g.Gene, want)
+	}
+}
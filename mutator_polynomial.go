@@ -0,0 +1,50 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Bounded polynomial mutation as proposed by Deb and used together with
+simulated binary crossover in NSGA-II. Needs a finite [Min,Max] range.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+type GAPolynomialMutator struct {
+	// Distribution index, larger values give children closer to the parent.
+	Eta float64
+	// Chance of each gene being mutated, 0 means 1/n.
+	PGene float64
+	// Handling of genes that leave the range. The polynomial distribution
+	// never leaves the range, so this only matters for genes that were
+	// outside already.
+	Bound GABoundHandling
+}
+
+func NewGAPolynomialMutator(eta, pgene float64) *GAPolynomialMutator {
+	return &GAPolynomialMutator{Eta: eta, PGene: pgene, Bound: GABoundClamp}
+}
+
+func (m GAPolynomialMutator) Mutate(a GAGenome) GAGenome {
+	return mutateReal(a, m.PGene, m.Bound, func(x, min, max float64) float64 {
+		if max <= min {
+			return x
+		}
+		d := max - min
+		d1, d2 := (x-min)/d, (max-x)/d
+		p := 1 / (m.Eta + 1)
+		u := rng.Float64()
+		var dq float64
+		if u < 0.5 {
+			v := 2*u + (1-2*u)*math.Pow(1-d1, m.Eta+1)
+			dq = math.Pow(v, p) - 1
+		} else {
+			v := 2*(1-u) + 2*(u-0.5)*math.Pow(1-d2, m.Eta+1)
+			dq = 1 - math.Pow(v, p)
+		}
+		return x + dq*d
+	})
+}
+func (m GAPolynomialMutator) String() string { return "GAPolynomialMutator" }
@@ -50,3 +50,48 @@ func TestRandomMutatorUniform(t *testing.T) {
 		t.Errorf("Gene replacement counters: %v", count)
 	}
 }
+
+// Genome wrapper without RandomizeGene, to exercise the copy and splice path.
+type spliceOnlyGenome struct{ g *GAFloatGenome }
+
+func (s spliceOnlyGenome) Randomize()      { s.g.Randomize() }
+func (s spliceOnlyGenome) Copy() GAGenome  { return spliceOnlyGenome{s.g.Copy().(*GAFloatGenome)} }
+func (s spliceOnlyGenome) Score() float64  { return s.g.Score() }
+func (s spliceOnlyGenome) Reset()          { s.g.Reset() }
+func (s spliceOnlyGenome) Switch(x, y int) { s.g.Switch(x, y) }
+func (s spliceOnlyGenome) Valid() bool     { return s.g.Valid() }
+func (s spliceOnlyGenome) String() string  { return s.g.String() }
+func (s spliceOnlyGenome) Len() int        { return s.g.Len() }
+func (s spliceOnlyGenome) Crossover(bi GAGenome, p1, p2 int) (GAGenome, GAGenome) {
+	return s.g.Crossover(bi.(spliceOnlyGenome).g, p1, p2)
+}
+func (s spliceOnlyGenome) Splice(bi GAGenome, from, to, length int) {
+	s.g.Splice(bi.(spliceOnlyGenome).g, from, to, length)
+}
+
+func TestRandomMutatorSpliceFallback(t *testing.T) {
+	g := spliceOnlyGenome{NewFloatGenome([]float64{0, 0}, nil, 10, 10)}
+	gn := GAMutatorRandom{}.Mutate(g).(spliceOnlyGenome)
+	if !reflect.DeepEqual(gn.g.Gene, []float64{0, 10}) &&
+		!reflect.DeepEqual(gn.g.Gene, []float64{10, 0}) {
+		t.Errorf("GAMutatorRandom.Mutate(%v) = %v; want {0, 10} or {10, 0}", g.g.Gene, gn.g.Gene)
+	}
+}
+
+func benchmarkRandomMutator(b *testing.B, g GAGenome) {
+	m := GAMutatorRandom{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.Mutate(g)
+	}
+}
+
+// Compares mutating a large genome through RandomizeGene with randomizing and
+// splicing a full copy.
+func BenchmarkRandomMutatorGene(b *testing.B) {
+	benchmarkRandomMutator(b, NewFloatGenome(make([]float64, 10000), nil, 1, 0))
+}
+
+func BenchmarkRandomMutatorSplice(b *testing.B) {
+	benchmarkRandomMutator(b, spliceOnlyGenome{NewFloatGenome(make([]float64, 10000), nil, 1, 0)})
+}
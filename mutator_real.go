@@ -0,0 +1,105 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Helpers shared by the mutators for real valued genomes.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+// What a real valued mutator does with a gene that ends up outside the
+// [Min,Max] range of the genome.
+type GABoundHandling int
+
+const (
+	// Leave the gene outside the range.
+	GABoundNone GABoundHandling = iota
+	// Set the gene to the nearest bound.
+	GABoundClamp
+	// Mirror the gene back into the range at the bound it crossed.
+	GABoundReflect
+	// Replace the gene with a random value in the range.
+	GABoundRandom
+)
+
+// apply returns x moved into [min,max] according to b.
+func (b GABoundHandling) apply(x, min, max float64) float64 {
+	if b == GABoundNone || (x >= min && x <= max) {
+		return x
+	}
+	if max <= min {
+		return min
+	}
+	switch b {
+	case GABoundClamp:
+		return math.Max(min, math.Min(max, x))
+	case GABoundReflect:
+		// Reflecting repeatedly is a triangle wave with period 2*(max-min).
+		w := max - min
+		d := math.Mod(x-min, 2*w)
+		if d < 0 {
+			d += 2 * w
+		}
+		if d > w {
+			d = 2*w - d
+		}
+		return min + d
+	case GABoundRandom:
+		return min + rng.Float64()*(max-min)
+	}
+	return x
+}
+
+// mutateReal returns a mutated copy of a, which must be a GAFloatGenome or a
+// GAFloat32Genome. Each gene is passed to f with chance pgene, or 1/n if pgene
+// is 0, and at least one gene is always changed. f gets the gene and the
+// range of the genome and returns the new gene, which is then bounded by b.
+func mutateReal(a GAGenome, pgene float64, b GABoundHandling, f func(x, min, max float64) float64) GAGenome {
+	var gene []float64
+	var min, max float64
+	switch a := a.(type) {
+	case *GAFloatGenome:
+		gene, min, max = a.Gene, a.Min, a.Max
+	case *GAFloat32Genome:
+		gene = make([]float64, len(a.Gene))
+		for i, c := range a.Gene {
+			gene[i] = float64(c)
+		}
+		min, max = float64(a.Min), float64(a.Max)
+	default:
+		panic("Real valued mutator needs a GAFloatGenome or GAFloat32Genome")
+	}
+	n := a.Copy()
+	l := len(gene)
+	if l == 0 {
+		return n
+	}
+	if pgene == 0 {
+		pgene = 1 / float64(l)
+	}
+	set := func(i int) {
+		v := b.apply(f(gene[i], min, max), min, max)
+		switch n := n.(type) {
+		case *GAFloatGenome:
+			n.Gene[i] = v
+		case *GAFloat32Genome:
+			n.Gene[i] = float32(v)
+		}
+	}
+	mutated := false
+	for i := 0; i < l; i++ {
+		if pgene > rng.Float64() {
+			set(i)
+			mutated = true
+		}
+	}
+	if !mutated {
+		set(rng.Intn(l))
+	}
+	n.Reset()
+	return n
+}
@@ -0,0 +1,93 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundHandling(t *testing.T) {
+	tests := []struct {
+		b       GABoundHandling
+		x, want float64
+	}{
+		{GABoundNone, 12, 12},
+		{GABoundClamp, 12, 10},
+		{GABoundClamp, -3, 0},
+		{GABoundClamp, 5, 5},
+		{GABoundReflect, 12, 8},
+		{GABoundReflect, -3, 3},
+		{GABoundReflect, 23, 3},
+		{GABoundReflect, -13, 7},
+	}
+	for _, test := range tests {
+		if got := test.b.apply(test.x, 0, 10); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("GABoundHandling(%d).apply(%v, 0, 10) = %v; want %v", test.b, test.x, got, test.want)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if got := GABoundRandom.apply(-5, 0, 10); got < 0 || got > 10 {
+			t.Fatalf("GABoundRandom.apply(-5, 0, 10) = %v; want in [0,10]", got)
+		}
+	}
+}
+
+// Tests that the real valued mutators keep genes in range when asked to and
+// change genes with the requested per gene chance.
+func TestRealMutators(t *testing.T) {
+	mutators := []struct {
+		m     GAMutator
+		pgene float64
+	}{
+		{NewGAPolynomialMutator(20, 0), 0},
+		{&GAPolynomialMutator{Eta: 5, PGene: 0.5}, 0.5},
+		{&GACauchyMutator{Scale: 5, PGene: 0.3, Bound: GABoundReflect}, 0.3},
+		{&GALevyMutator{Beta: 1.5, Scale: 5, PGene: 1, Bound: GABoundClamp}, 1},
+		{&GALevyMutator{Beta: 1, Scale: 1, Bound: GABoundRandom}, 0},
+	}
+	const l, iters = 20, 5000
+	for _, test := range mutators {
+		g := NewFloatGenome(make([]float64, l), nil, 1, -1)
+		changed := 0
+		for i := 0; i < iters; i++ {
+			c := test.m.Mutate(g).(*GAFloatGenome)
+			for _, v := range c.Gene {
+				if v < -1 || v > 1 {
+					t.Fatalf("%s.Mutate(%v) = %v; want genes in [-1,1]", test.m, g.Gene, c.Gene)
+				}
+				if v != 0 {
+					changed++
+				}
+			}
+		}
+		p := test.pgene
+		if p == 0 {
+			p = 1.0 / l
+		}
+		// At least one gene is always changed, one more when none was picked.
+		want := (l*p + math.Pow(1-p, l)) / l
+		got := float64(changed) / (l * iters)
+		if math.Abs(got-want) > want*0.1 {
+			t.Errorf("%s with PGene %v changed %v of the genes; want about %v", test.m, test.pgene, got, want)
+		}
+	}
+}
+
+func TestRealMutatorsFloat32(t *testing.T) {
+	g := NewFloat32Genome(make([]float32, 10), nil, 1, -1)
+	for _, m := range []GAMutator{
+		NewGAPolynomialMutator(20, 0),
+		NewGACauchyMutator(0.1, 0),
+		NewGALevyMutator(1.5, 0.1, 0),
+	} {
+		c := m.Mutate(g).(*GAFloat32Genome)
+		changed := 0
+		for i, v := range c.Gene {
+			if v != g.Gene[i] {
+				changed++
+			}
+		}
+		if changed == 0 {
+			t.Errorf("%s.Mutate(%v) = %v; want at least one changed gene", m, g.Gene, c.Gene)
+		}
+	}
+}
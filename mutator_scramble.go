@@ -0,0 +1,33 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+This mutator copies the genome and shuffles a random segment of
+the copy. Works on any genome and keeps permutations valid.
+*/
+
+package ga
+
+type GAScrambleMutator struct{}
+
+func (m GAScrambleMutator) Mutate(a GAGenome) GAGenome {
+	n := a.Copy()
+	l := a.Len()
+	if l < 2 {
+		return n
+	}
+	p1 := rng.Intn(l)
+	p2 := rng.Intn(l)
+	if p1 > p2 {
+		p1, p2 = p2, p1
+	}
+	//Fisher-Yates shuffle of the segment
+	for i := p2; i > p1; i-- {
+		j := p1 + rng.Intn(i-p1+1)
+		if i != j {
+			n.Switch(i, j)
+		}
+	}
+	return n
+}
+func (m GAScrambleMutator) String() string { return "GAScrambleMutator" }
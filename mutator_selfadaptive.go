@@ -0,0 +1,288 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Self-adaptive gaussian mutation as used in evolution strategies. Every
+genome carries its own step size(s) in Sigma which are mutated along with
+the genes, so good step sizes are inherited by good genomes.
+*/
+
+package ga
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"sync"
+)
+
+type GASelfAdaptiveMutator struct {
+	// Initial step size for genomes without Sigma, 0 uses (Max-Min)/10.
+	Sigma0 float64
+	// Smallest step size allowed.
+	MinSigma float64
+	// Give every gene its own step size instead of one for the whole genome.
+	PerGene bool
+	// Chance of each gene being mutated, 0 mutates all genes. At least one
+	// gene is always mutated.
+	PGene float64
+	// Learning rates of the log-normal update. 0 uses 1/sqrt(2*sqrt(n)) for
+	// Tau and 1/sqrt(2*n) for TauPrime, or 1/sqrt(n) for TauPrime when there
+	// is a single step size.
+	Tau, TauPrime float64
+	// Adapt the step sizes with the one-fifth success rule instead of the
+	// log-normal update. The step sizes of the genomes are then left alone
+	// and all scaled by a factor of the mutator, which grows for every child
+	// that scores better than its parent and shrinks for every other one, so
+	// it is stable at a success rate of one in five. Children are counted
+	// once their score is cached, the mutator never evaluates a genome.
+	OneFifth bool
+
+	// Guards success, the mutator may be shared by goroutines
+	mu      sync.Mutex
+	success *gaSuccess
+}
+
+// Children of a one-fifth success rule mutator that have not been counted
+// yet and the step size factor of the mutator.
+type gaSuccess struct {
+	pending []GAGenome
+	// Score of the parent of each pending child
+	parent []float64
+	scale  float64
+}
+
+func NewGASelfAdaptiveMutator(sigma0 float64, pergene bool) *GASelfAdaptiveMutator {
+	return &GASelfAdaptiveMutator{Sigma0: sigma0, PerGene: pergene}
+}
+
+func (m *GASelfAdaptiveMutator) Mutate(a GAGenome) GAGenome {
+	switch a := a.(type) {
+	case *GAFloatGenome:
+		n := a.Copy().(*GAFloatGenome)
+		n.Sigma = m.init(n.Sigma, len(n.Gene), n.Max-n.Min)
+		m.mutate(n.Gene, n.Sigma)
+		n.Reset()
+		if m.OneFifth {
+			m.record(a, n)
+		}
+		return n
+	case *GAFloat32Genome:
+		n := a.Copy().(*GAFloat32Genome)
+		gene := make([]float64, len(n.Gene))
+		for i, c := range n.Gene {
+			gene[i] = float64(c)
+		}
+		sigma := make([]float64, len(n.Sigma))
+		for i, c := range n.Sigma {
+			sigma[i] = float64(c)
+		}
+		sigma = m.init(sigma, len(gene), float64(n.Max-n.Min))
+		m.mutate(gene, sigma)
+		for i, c := range gene {
+			n.Gene[i] = float32(c)
+		}
+		n.Reset()
+		n.Sigma = make([]float32, len(sigma))
+		for i, c := range sigma {
+			n.Sigma[i] = float32(c)
+		}
+		if m.OneFifth {
+			m.record(a, n)
+		}
+		return n
+	}
+	panic("Self-adaptive mutator needs a GAFloatGenome or GAFloat32Genome")
+}
+
+// init returns sigma, or new step sizes if sigma does not fit the mutator.
+func (m *GASelfAdaptiveMutator) init(sigma []float64, l int, span float64) []float64 {
+	want := 1
+	if m.PerGene {
+		want = l
+	}
+	if len(sigma) == want {
+		return sigma
+	}
+	s0 := m.Sigma0
+	if s0 == 0 {
+		s0 = math.Abs(span) / 10
+	}
+	if s0 == 0 {
+		s0 = 1
+	}
+	sigma = make([]float64, want)
+	for i := range sigma {
+		sigma[i] = s0
+	}
+	return sigma
+}
+
+func (m *GASelfAdaptiveMutator) mutate(gene, sigma []float64) {
+	l := len(gene)
+	if l == 0 {
+		return
+	}
+	// Pick the genes to mutate first, per gene step sizes of genes that are
+	// left alone are not under selection and must not drift.
+	mutate := make([]bool, l)
+	mutated := false
+	for i := range mutate {
+		mutate[i] = m.PGene == 0 || m.PGene > rng.Float64()
+		mutated = mutated || mutate[i]
+	}
+	if !mutated {
+		mutate[rng.Intn(l)] = true
+	}
+	scale := 1.0
+	if m.OneFifth {
+		scale = m.scale()
+	} else {
+		n := float64(l)
+		tau, tauprime := m.Tau, m.TauPrime
+		if tau == 0 {
+			tau = 1 / math.Sqrt(2*math.Sqrt(n))
+		}
+		if tauprime == 0 {
+			if len(sigma) == 1 {
+				tauprime = 1 / math.Sqrt(n)
+			} else {
+				tauprime = 1 / math.Sqrt(2*n)
+			}
+		}
+		global := tauprime * rng.NormFloat64()
+		for i := range sigma {
+			if len(sigma) == 1 {
+				sigma[i] *= math.Exp(global)
+			} else if mutate[i] {
+				sigma[i] *= math.Exp(global + tau*rng.NormFloat64())
+			}
+			if sigma[i] < m.MinSigma {
+				sigma[i] = m.MinSigma
+			}
+		}
+	}
+	for i := range gene {
+		if !mutate[i] {
+			continue
+		}
+		step := sigma[0]
+		if len(sigma) > 1 {
+			step = sigma[i]
+		}
+		gene[i] += math.Max(step*scale, m.MinSigma) * rng.NormFloat64()
+	}
+}
+
+// cached returns the score of g if it is cached.
+func cached(g GAGenome) (float64, bool) {
+	if s, ok := g.(GAScoreSetter); ok && s.HasScore() {
+		return g.Score(), true
+	}
+	return 0, false
+}
+
+// record remembers child to count whether it beats parent once its score is
+// cached. Children of unscored parents are not counted.
+func (m *GASelfAdaptiveMutator) record(parent, child GAGenome) {
+	score, ok := cached(parent)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.state()
+	s.pending = append(s.pending, child)
+	s.parent = append(s.parent, score)
+}
+
+// state returns the one-fifth rule state, m.mu must be held.
+func (m *GASelfAdaptiveMutator) state() *gaSuccess {
+	if m.success == nil {
+		m.success = &gaSuccess{scale: 1}
+	}
+	return m.success
+}
+
+// scale applies the one-fifth success rule for the children scored since
+// the last call and returns the step size factor.
+func (m *GASelfAdaptiveMutator) scale() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count()
+}
+
+// count updates the step size factor for every pending child whose score is
+// cached and returns it, m.mu must be held.
+func (m *GASelfAdaptiveMutator) count() float64 {
+	s := m.state()
+	k := 0
+	for i, c := range s.pending {
+		score, ok := cached(c)
+		d := math.Sqrt(float64(c.Len()) + 1)
+		switch {
+		case !ok:
+			s.pending[k], s.parent[k] = c, s.parent[i]
+			k++
+		case score < s.parent[i]:
+			s.scale *= math.Exp(1 / d)
+		default:
+			s.scale *= math.Exp(-0.25 / d)
+		}
+	}
+	// Children that are never scored, for example because they were thrown
+	// away first, are forgotten after a while.
+	if k > 100 {
+		copy(s.pending, s.pending[k-100:k])
+		copy(s.parent, s.parent[k-100:k])
+		k = 100
+	}
+	for i := k; i < len(s.pending); i++ {
+		s.pending[i] = nil
+	}
+	s.pending, s.parent = s.pending[:k], s.parent[:k]
+	return s.scale
+}
+
+// Clone returns a copy of the mutator with its own step size factor and
+// record of children.
+func (m *GASelfAdaptiveMutator) Clone() interface{} {
+	return &GASelfAdaptiveMutator{
+		Sigma0:   m.Sigma0,
+		MinSigma: m.MinSigma,
+		PerGene:  m.PerGene,
+		PGene:    m.PGene,
+		Tau:      m.Tau,
+		TauPrime: m.TauPrime,
+		OneFifth: m.OneFifth,
+	}
+}
+
+type gaSelfAdaptiveState struct {
+	Scale float64
+}
+
+// MarshalBinary saves the step size factor of the one-fifth success rule,
+// for checkpoints, after counting the children scored so far. Children that
+// are not scored yet are not saved.
+func (m *GASelfAdaptiveMutator) MarshalBinary() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(gaSelfAdaptiveState{m.count()})
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary restores the step size factor saved by MarshalBinary.
+func (m *GASelfAdaptiveMutator) UnmarshalBinary(data []byte) error {
+	var st gaSelfAdaptiveState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.success = &gaSuccess{scale: st.Scale}
+	return nil
+}
+
+func (m *GASelfAdaptiveMutator) String() string { return "GASelfAdaptiveMutator" }
@@ -0,0 +1,122 @@
+package ga
+
+import (
+	"sync"
+	"testing"
+)
+
+func sphere(g *GAFloatGenome) float64 {
+	var s float64
+	for _, c := range g.Gene {
+		s += c * c
+	}
+	return s
+}
+
+// Tests that a simple (1,10) evolution strategy on the sphere function
+// converges, which only happens when the step sizes shrink along the way.
+func TestSelfAdaptiveMutatorConverges(t *testing.T) {
+	mutators := []*GASelfAdaptiveMutator{
+		{Sigma0: 1},
+		{Sigma0: 1, PerGene: true},
+		{Sigma0: 1, OneFifth: true},
+		{Sigma0: 1, PGene: 0.5},
+	}
+	for n, m := range mutators {
+		var g GAGenome = NewFloatGenome([]float64{5, -5, 5, -5, 5}, sphere, 10, -10)
+		for i := 0; i < 1000; i++ {
+			var best GAGenome
+			for j := 0; j < 10; j++ {
+				if c := m.Mutate(g); best == nil || c.Score() < best.Score() {
+					best = c
+				}
+			}
+			g = best
+		}
+		if s := g.Score(); s > 1e-3 {
+			t.Errorf("mutators[%d]: score after 1000 generations = %v; want <= 1e-3", n, s)
+		}
+		want := 1
+		if m.PerGene {
+			want = g.Len()
+		}
+		if got := len(g.(*GAFloatGenome).Sigma); got != want {
+			t.Errorf("mutators[%d]: len(Sigma) = %d; want %d", n, got, want)
+		}
+	}
+}
+
+func TestSelfAdaptiveMutatorFloat32(t *testing.T) {
+	g := NewFloat32Genome([]float32{1, 2, 3}, func(g *GAFloat32Genome) float32 { return g.Gene[0] }, 10, 0)
+	m := NewGASelfAdaptiveMutator(0, true)
+	c := m.Mutate(g).(*GAFloat32Genome)
+	if len(c.Sigma) != 3 || c.Sigma[0] == 0 {
+		t.Errorf("Sigma = %v; want 3 positive step sizes", c.Sigma)
+	}
+	if g.Sigma != nil {
+		t.Errorf("Mutate modified the parent Sigma: %v", g.Sigma)
+	}
+}
+
+// Tests the one-fifth success rule in a (1+1) evolution strategy, where
+// failed children are thrown away, on genomes scored only through SetScore.
+// The parents must be left alone.
+func TestSelfAdaptiveMutatorOneFifth(t *testing.T) {
+	m := &GASelfAdaptiveMutator{Sigma0: 1, OneFifth: true}
+	g := NewFloatGenome([]float64{5, -5, 5, -5, 5}, nil, 10, -10)
+	g.SetScore(sphere(g))
+	for i := 0; i < 2000; i++ {
+		sigma := append([]float64(nil), g.Sigma...)
+		c := m.Mutate(g).(*GAFloatGenome)
+		if len(g.Sigma) != len(sigma) || len(sigma) > 0 && g.Sigma[0] != sigma[0] {
+			t.Fatalf("Mutate changed the parent step size from %v to %v", sigma, g.Sigma)
+		}
+		c.SetScore(sphere(c))
+		if c.Score() < g.Score() {
+			g = c
+		}
+	}
+	if s := g.Score(); s > 1e-3 {
+		t.Errorf("score after 2000 children = %v; want <= 1e-3", s)
+	}
+}
+
+// Tests that a one-fifth rule mutator can be shared by goroutines and that
+// its step size factor survives a checkpoint.
+func TestSelfAdaptiveMutatorOneFifthState(t *testing.T) {
+	m := &GASelfAdaptiveMutator{Sigma0: 1, OneFifth: true}
+	parent := NewFloatGenome([]float64{5, -5, 5}, nil, 10, -10)
+	parent.SetScore(sphere(parent))
+	var mu sync.Mutex
+	var children []*GAFloatGenome
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				c := m.Mutate(parent).(*GAFloatGenome)
+				mu.Lock()
+				children = append(children, c)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for _, c := range children {
+		c.SetScore(sphere(c))
+	}
+	// Counts the children.
+	m.Mutate(parent)
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+	n := m.Clone().(*GASelfAdaptiveMutator)
+	if err := n.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() = %v", err)
+	}
+	if n.success.scale != m.success.scale || m.success.scale == 1 {
+		t.Errorf("restored step size factor %v; want %v, not 1", n.success.scale, m.success.scale)
+	}
+}
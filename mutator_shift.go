@@ -8,16 +8,12 @@ Shifts the whole genome random length to the right.
 
 package ga
 
-import (
-	"math/rand"
-)
-
 type GAShiftMutator struct{}
 
 func (m GAShiftMutator) Mutate(a GAGenome) GAGenome {
 	n := a.Copy()
 	l := a.Len()
-	s := rand.Intn(l / 2)
+	s := rng.Intn(l / 2)
 	n.Splice(a, l-s, 0, s)
 	n.Splice(a, 0, l-s, s)
 	return n
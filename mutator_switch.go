@@ -9,16 +9,12 @@ the copy and returns the new mutated copy.
 
 package ga
 
-import (
-	"math/rand"
-)
-
 type GASwitchMutator struct{}
 
 func (m GASwitchMutator) Mutate(a GAGenome) GAGenome {
 	n := a.Copy()
-	p1 := rand.Intn(a.Len())
-	p2 := rand.Intn(a.Len())
+	p1 := rng.Intn(a.Len())
+	p2 := rng.Intn(a.Len())
 	if p1 > p2 {
 		p1, p2 = p2, p1
 	}
@@ -1,8 +1,6 @@
 package ga
 
 import (
-	"math/rand"
-
 	"github.com/pointlander/gobrain"
 )
 
@@ -24,6 +22,7 @@ type GAFeedForwardNeural struct {
 	Experts []GAExpert
 	Noise   float32
 	Single  bool
+	width   int
 }
 
 const (
@@ -37,7 +36,19 @@ func NewGAFeedForwardNeural(noise float32, experts, width int, dropout float32,
 		_experts[e].Dropout = dropout
 		_experts[e].Regression = regression
 	}
-	return &GAFeedForwardNeural{Experts: _experts, Noise: noise}
+	return &GAFeedForwardNeural{Experts: _experts, Noise: noise, width: width}
+}
+
+// Clone returns an untrained network of the same shape, for the islands of a
+// GAParallel. A network not made by NewGAFeedForwardNeural is returned as
+// is.
+func (n *GAFeedForwardNeural) Clone() interface{} {
+	if n.width == 0 || len(n.Experts) == 0 {
+		return n
+	}
+	c := NewGAFeedForwardNeural(n.Noise, len(n.Experts), n.width, n.Experts[0].Dropout, n.Experts[0].Regression)
+	c.Single = n.Single
+	return c
 }
 
 func (n *GAFeedForwardNeural) Train(genomes GAGenomes, selector GASelector) {
@@ -75,9 +86,9 @@ func (n *GAFeedForwardNeural) Morph(genome GAGenome) GAGenome {
 	}
 	noise := make([]float32, width+width/2+width)
 	_noise := [][]float32{noise[:width], noise[width : width+width/2], noise[width+width/2:]}
-	ff := rand.Intn(len(n.Experts))
+	ff := rng.Intn(len(n.Experts))
 	for i := range _noise[0] {
-		n := n.Noise * float32(rand.NormFloat64()) / n.Experts[ff].mse
+		n := n.Noise * float32(rng.NormFloat64()) / n.Experts[ff].mse
 		_noise[0][i] = n
 		_noise[2][i] = n
 	}
@@ -85,9 +96,9 @@ func (n *GAFeedForwardNeural) Morph(genome GAGenome) GAGenome {
 
 	cp := source.Copy().(*GAFloat32Genome)
 	if n.Single {
-		mutations := int(rand.NormFloat64()) + 1
+		mutations := int(rng.NormFloat64()) + 1
 		for m := 0; m < mutations; m++ {
-			i := rand.Intn(len(cp.Gene))
+			i := rng.Intn(len(cp.Gene))
 			cp.Gene[i] = morphed[i]
 		}
 	} else {
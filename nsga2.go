@@ -0,0 +1,111 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+NSGA-II, the non-dominated sorting genetic algorithm of Deb et al. Parents
+and children are sorted into Pareto fronts together and the best fronts
+survive, the last one thinned out by crowding distance so the survivors
+spread along the front.
+*/
+
+package ga
+
+import (
+	"sort"
+)
+
+type GANSGA2 struct {
+	// Initializer, Breeder, Mutator, PBreed and PMutate are used as by GA.
+	// Parents are picked by binary tournament on front and crowding
+	// distance, the Selector is not used.
+	Parameter GAParameter
+	// Objective vector of a genome, every objective is minimized. Only used
+	// for genomes that do not implement GAObjectiveGenome.
+	Objectives func(g GAGenome) []float64
+	// Constraint violation of a genome, 0 if it is feasible, nil if there
+	// are no constraints. Only used for genomes that do not implement
+	// GAConstrainedGenome.
+	Violation func(g GAGenome) float64
+
+	pop        []*gaIndividual
+	popsize    int
+	generation int
+}
+
+func NewGANSGA2(parameter GAParameter, objectives func(g GAGenome) []float64) *GANSGA2 {
+	return &GANSGA2{Parameter: parameter, Objectives: objectives}
+}
+
+func (ga *GANSGA2) String() string { return "GANSGA2" }
+
+func (ga *GANSGA2) Init(popsize int, i GAGenome) {
+	ga.popsize = popsize
+	ga.generation = 0
+	ga.pop = individuals(ga.Parameter.Initializer.InitPop(i, popsize), ga.Objectives, ga.Violation)
+	ga.pop = ga.survivors(ga.pop)
+}
+
+func (ga *GANSGA2) Optimize(gen int) {
+	for i := 0; i < gen; i++ {
+		var q GAGenomes
+		for len(q) < ga.popsize {
+			q = append(q, offspring(&ga.Parameter, ga.tournament().g, ga.tournament().g)...)
+		}
+		children := individuals(q[:ga.popsize], ga.Objectives, ga.Violation)
+		ga.pop = ga.survivors(append(ga.pop, children...))
+		ga.generation++
+	}
+}
+
+// tournament returns the better of two random individuals, by front and then
+// by crowding distance.
+func (ga *GANSGA2) tournament() *gaIndividual {
+	a, b := ga.pop[rng.Intn(len(ga.pop))], ga.pop[rng.Intn(len(ga.pop))]
+	switch {
+	case a.rank != b.rank:
+		if a.rank < b.rank {
+			return a
+		}
+		return b
+	case a.crowding != b.crowding:
+		if a.crowding > b.crowding {
+			return a
+		}
+		return b
+	}
+	if rng.Intn(2) == 0 {
+		return a
+	}
+	return b
+}
+
+// survivors returns the popsize best individuals of r by front and crowding
+// distance.
+func (ga *GANSGA2) survivors(r []*gaIndividual) []*gaIndividual {
+	next := make([]*gaIndividual, 0, ga.popsize)
+	for _, f := range nonDominatedSort(r) {
+		crowdingDistance(f)
+		if len(next)+len(f) <= ga.popsize {
+			next = append(next, f...)
+			continue
+		}
+		sort.Slice(f, func(i, j int) bool { return f[i].crowding > f[j].crowding })
+		next = append(next, f[:ga.popsize-len(next)]...)
+		break
+	}
+	return next
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GANSGA2) Generation() int { return ga.generation }
+
+// Population returns the genomes of the population.
+func (ga *GANSGA2) Population() GAGenomes { return genomesOf(ga.pop) }
+
+// ParetoFront returns the genomes of the population no other genome of the
+// population dominates.
+func (ga *GANSGA2) ParetoFront() GAGenomes { return genomesOf(paretoFront(ga.pop)) }
+
+// ParetoObjectives returns the objective vectors of the ParetoFront, in the
+// same order.
+func (ga *GANSGA2) ParetoObjectives() [][]float64 { return objectivesOf(paretoFront(ga.pop)) }
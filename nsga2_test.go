@@ -0,0 +1,82 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+// zdt1 is the first test problem of Zitzler, Deb and Thiele, with the
+// Pareto front f2 = 1 - sqrt(f1) where every gene but the first is 0.
+func zdt1(g GAGenome) []float64 {
+	x := g.(*GAFloatGenome).Gene
+	s := 0.0
+	for _, v := range x[1:] {
+		s += v
+	}
+	h := 1 + 9*s/float64(len(x)-1)
+	return []float64{x[0], h * (1 - math.Sqrt(x[0]/h))}
+}
+
+// checkZDT1 checks that nearly every point of the front is close to the true
+// front and that the front spreads over most of it. A point with the
+// smallest f1 is never dominated however far above the front it is, so a
+// few may lag behind.
+func checkZDT1(t *testing.T, name string, front [][]float64) {
+	if len(front) < 10 {
+		t.Fatalf("%s: Pareto front of %d points; want at least 10", name, len(front))
+	}
+	lo, hi, far := math.Inf(1), math.Inf(-1), 0
+	for _, f := range front {
+		if f[1]-(1-math.Sqrt(f[0])) > 0.1 {
+			far++
+		}
+		lo, hi = math.Min(lo, f[0]), math.Max(hi, f[0])
+	}
+	if far > len(front)/10 {
+		t.Errorf("%s: %d of %d front points more than 0.1 above the true front", name, far, len(front))
+	}
+	if hi-lo < 0.7 {
+		t.Errorf("%s: front covers f1 from %v to %v; want a spread of at least 0.7", name, lo, hi)
+	}
+}
+
+func moeaParameter() GAParameter {
+	return GAParameter{
+		Initializer: new(GARandomInitializer),
+		Breeder:     new(GAUniformBreeder),
+		Mutator:     NewGAPolynomialMutator(20, 0),
+		PBreed:      0.9,
+		PMutate:     1}
+}
+
+func TestNSGA2(t *testing.T) {
+	ga := NewGANSGA2(moeaParameter(), zdt1)
+	ga.Init(60, NewFloatGenome(make([]float64, 8), nil, 1, 0))
+	ga.Optimize(200)
+	if ga.Generation() != 200 || len(ga.Population()) != 60 {
+		t.Errorf("generation %d with %d genomes; want 200 and 60", ga.Generation(), len(ga.Population()))
+	}
+	front := ga.ParetoObjectives()
+	if len(front) != len(ga.ParetoFront()) {
+		t.Errorf("%d objective vectors for %d genomes", len(front), len(ga.ParetoFront()))
+	}
+	checkZDT1(t, "NSGA-II", front)
+}
+
+func TestNSGA2Constrained(t *testing.T) {
+	// Minimize x and 1-x with x at least 0.6.
+	ga := NewGANSGA2(moeaParameter(), func(g GAGenome) []float64 {
+		x := g.(*GAFloatGenome).Gene[0]
+		return []float64{x, 1 - x}
+	})
+	ga.Violation = func(g GAGenome) float64 {
+		return math.Max(0, 0.6-g.(*GAFloatGenome).Gene[0])
+	}
+	ga.Init(20, NewFloatGenome(make([]float64, 1), nil, 1, 0))
+	ga.Optimize(30)
+	for _, g := range ga.ParetoFront() {
+		if x := g.(*GAFloatGenome).Gene[0]; x < 0.6 {
+			t.Errorf("infeasible genome %v on the Pareto front", x)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+NSGA-III of Deb and Jain, for problems with many objectives. Survivors are
+picked by front as in NSGA-II, but the last front is thinned out by
+associating genomes with reference points on the normalized objective space
+and preferring the reference points with the fewest genomes, which keeps
+the front spread where crowding distance no longer does.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+type GANSGA3 struct {
+	// Initializer, Breeder, Mutator, PBreed and PMutate are used as by GA.
+	// Parents are picked at random, the Selector is not used.
+	Parameter GAParameter
+	// Objective vector of a genome, every objective is minimized. Only used
+	// for genomes that do not implement GAObjectiveGenome.
+	Objectives func(g GAGenome) []float64
+	// Constraint violation of a genome, 0 if it is feasible, nil if there
+	// are no constraints. Only used for genomes that do not implement
+	// GAConstrainedGenome.
+	Violation func(g GAGenome) float64
+	// Reference points on the unit simplex, for example from DasDennis
+	Reference [][]float64
+
+	pop        []*gaIndividual
+	popsize    int
+	generation int
+}
+
+func NewGANSGA3(parameter GAParameter, objectives func(g GAGenome) []float64, reference [][]float64) *GANSGA3 {
+	return &GANSGA3{Parameter: parameter, Objectives: objectives, Reference: reference}
+}
+
+func (ga *GANSGA3) String() string { return "GANSGA3" }
+
+// Init creates a population of popsize genomes, usually about as many as
+// there are reference points.
+func (ga *GANSGA3) Init(popsize int, i GAGenome) {
+	if len(ga.Reference) == 0 {
+		panic("No reference points")
+	}
+	ga.popsize = popsize
+	ga.generation = 0
+	ga.pop = individuals(ga.Parameter.Initializer.InitPop(i, popsize), ga.Objectives, ga.Violation)
+}
+
+func (ga *GANSGA3) Optimize(gen int) {
+	for i := 0; i < gen; i++ {
+		var q GAGenomes
+		for len(q) < ga.popsize {
+			a, b := ga.pop[rng.Intn(len(ga.pop))], ga.pop[rng.Intn(len(ga.pop))]
+			q = append(q, offspring(&ga.Parameter, a.g, b.g)...)
+		}
+		children := individuals(q[:ga.popsize], ga.Objectives, ga.Violation)
+		ga.pop = ga.survivors(append(ga.pop, children...))
+		ga.generation++
+	}
+}
+
+// survivors returns the popsize best individuals of r by front and niche.
+func (ga *GANSGA3) survivors(r []*gaIndividual) []*gaIndividual {
+	var s, last []*gaIndividual
+	for _, f := range nonDominatedSort(r) {
+		if len(s)+len(f) > ga.popsize {
+			last = f
+			break
+		}
+		s = append(s, f...)
+	}
+	if len(s) == ga.popsize || last == nil {
+		return s
+	}
+	all := append(append([]*gaIndividual(nil), s...), last...)
+	ref, dist := ga.associate(all)
+	niche := make([]int, len(ga.Reference))
+	for i := range s {
+		niche[ref[i]]++
+	}
+	// Members of the last front by reference point.
+	members := make([][]int, len(ga.Reference))
+	for i := len(s); i < len(all); i++ {
+		members[ref[i]] = append(members[ref[i]], i)
+	}
+	excluded := make([]bool, len(ga.Reference))
+	for len(s) < ga.popsize {
+		min, candidates := math.MaxInt32, []int(nil)
+		for j := range ga.Reference {
+			switch {
+			case excluded[j]:
+			case niche[j] < min:
+				min, candidates = niche[j], []int{j}
+			case niche[j] == min:
+				candidates = append(candidates, j)
+			}
+		}
+		j := candidates[rng.Intn(len(candidates))]
+		if len(members[j]) == 0 {
+			excluded[j] = true
+			continue
+		}
+		k := rng.Intn(len(members[j]))
+		if niche[j] == 0 {
+			for x, i := range members[j] {
+				if dist[i] < dist[members[j][k]] {
+					k = x
+				}
+			}
+		}
+		s = append(s, all[members[j][k]])
+		members[j] = append(members[j][:k], members[j][k+1:]...)
+		niche[j]++
+	}
+	return s
+}
+
+// associate returns for every individual of pop the nearest reference line
+// in the normalized objective space and the distance to it.
+func (ga *GANSGA3) associate(pop []*gaIndividual) (ref []int, dist []float64) {
+	norm := normalize(objectivesOf(pop))
+	ref, dist = make([]int, len(pop)), make([]float64, len(pop))
+	for i, f := range norm {
+		dist[i] = math.Inf(1)
+		for j, w := range ga.Reference {
+			if d := lineDistance(f, w); d < dist[i] {
+				ref[i], dist[i] = j, d
+			}
+		}
+	}
+	return
+}
+
+// normalize translates the objective vectors to the ideal point and scales
+// them by the intercepts of the hyperplane through the extreme points, or by
+// the largest translated objectives if there is no such hyperplane.
+func normalize(obj [][]float64) [][]float64 {
+	m := len(obj[0])
+	ideal := make([]float64, m)
+	for k := range ideal {
+		ideal[k] = math.Inf(1)
+		for _, f := range obj {
+			ideal[k] = math.Min(ideal[k], f[k])
+		}
+	}
+	t := make([][]float64, len(obj))
+	for i, f := range obj {
+		t[i] = make([]float64, m)
+		for k := range f {
+			t[i][k] = f[k] - ideal[k]
+		}
+	}
+	// Extreme point of every axis by achievement scalarizing function.
+	extreme := make([][]float64, m)
+	for k := 0; k < m; k++ {
+		best := math.Inf(1)
+		for _, f := range t {
+			asf := 0.0
+			for l, v := range f {
+				w := 1e-6
+				if l == k {
+					w = 1
+				}
+				asf = math.Max(asf, v/w)
+			}
+			if asf < best {
+				best, extreme[k] = asf, f
+			}
+		}
+	}
+	ones := make([]float64, m)
+	for k := range ones {
+		ones[k] = 1
+	}
+	intercept := make([]float64, m)
+	b, ok := solve(extreme, ones)
+	for k := range intercept {
+		if ok && b[k] > 1e-10 {
+			intercept[k] = 1 / b[k]
+		}
+		if intercept[k] < 1e-10 {
+			// Degenerate hyperplane, use the largest objective.
+			intercept[k] = 0
+			for _, f := range t {
+				intercept[k] = math.Max(intercept[k], f[k])
+			}
+			if intercept[k] < 1e-10 {
+				intercept[k] = 1
+			}
+		}
+	}
+	for _, f := range t {
+		for k := range f {
+			f[k] /= intercept[k]
+		}
+	}
+	return t
+}
+
+// solve returns x with a x = b by Gaussian elimination with partial
+// pivoting, false if a is singular.
+func solve(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = append(append([]float64(nil), a[i]...), b[i])
+	}
+	for c := 0; c < n; c++ {
+		p := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[p][c]) {
+				p = r
+			}
+		}
+		if math.Abs(m[p][c]) < 1e-12 {
+			return nil, false
+		}
+		m[c], m[p] = m[p], m[c]
+		for r := c + 1; r < n; r++ {
+			f := m[r][c] / m[c][c]
+			for k := c; k <= n; k++ {
+				m[r][k] -= f * m[c][k]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		s := m[r][n]
+		for k := r + 1; k < n; k++ {
+			s -= m[r][k] * x[k]
+		}
+		x[r] = s / m[r][r]
+	}
+	return x, true
+}
+
+// lineDistance returns the distance of f to the line through the origin in
+// direction w.
+func lineDistance(f, w []float64) float64 {
+	var fw, ww float64
+	for k := range f {
+		fw += f[k] * w[k]
+		ww += w[k] * w[k]
+	}
+	var d float64
+	for k := range f {
+		x := f[k] - fw/ww*w[k]
+		d += x * x
+	}
+	return math.Sqrt(d)
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GANSGA3) Generation() int { return ga.generation }
+
+// Population returns the genomes of the population.
+func (ga *GANSGA3) Population() GAGenomes { return genomesOf(ga.pop) }
+
+// ParetoFront returns the genomes of the population no other genome of the
+// population dominates.
+func (ga *GANSGA3) ParetoFront() GAGenomes { return genomesOf(paretoFront(ga.pop)) }
+
+// ParetoObjectives returns the objective vectors of the ParetoFront, in the
+// same order.
+func (ga *GANSGA3) ParetoObjectives() [][]float64 { return objectivesOf(paretoFront(ga.pop)) }
@@ -0,0 +1,122 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDasDennis(t *testing.T) {
+	w := DasDennis(3, 12)
+	if len(w) != 91 {
+		t.Errorf("len(DasDennis(3, 12)) = %d; want 91", len(w))
+	}
+	seen := make(map[[3]float64]bool)
+	for _, p := range w {
+		if s := p[0] + p[1] + p[2]; math.Abs(s-1) > 1e-12 {
+			t.Errorf("reference point %v sums to %v; want 1", p, s)
+		}
+		seen[[3]float64{p[0], p[1], p[2]}] = true
+	}
+	if len(seen) != 91 {
+		t.Errorf("DasDennis(3, 12) has %d distinct points; want 91", len(seen))
+	}
+	if n := len(DasDennis(5, 4)); n != 70 {
+		t.Errorf("len(DasDennis(5, 4)) = %d; want 70", n)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	// The extremes are (1,0,0)+1, (0,2,0)+1 and (0,0,4)+1.
+	norm := normalize([][]float64{{2, 1, 1}, {1, 3, 1}, {1, 1, 5}, {1.5, 2, 2}})
+	want := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {0.5, 0.5, 0.25}}
+	for i := range want {
+		for k := range want[i] {
+			if math.Abs(norm[i][k]-want[i][k]) > 1e-9 {
+				t.Errorf("normalize() = %v; want %v", norm, want)
+				return
+			}
+		}
+	}
+}
+
+// dtlz2 is the second problem of Deb, Thiele, Laumanns and Zitzler with 3
+// objectives, whose Pareto front is the positive part of the unit sphere.
+func dtlz2(g GAGenome) []float64 {
+	x := g.(*GAFloatGenome).Gene
+	var s float64
+	for _, v := range x[2:] {
+		s += (v - 0.5) * (v - 0.5)
+	}
+	a, b := x[0]*math.Pi/2, x[1]*math.Pi/2
+	return []float64{
+		(1 + s) * math.Cos(a) * math.Cos(b),
+		(1 + s) * math.Cos(a) * math.Sin(b),
+		(1 + s) * math.Sin(a),
+	}
+}
+
+// checkDTLZ2 checks that the front lies close to the unit sphere and covers
+// most of the reference directions, if there are any.
+func checkDTLZ2(t *testing.T, name string, front [][]float64, reference [][]float64) {
+	var dev float64
+	covered := make(map[int]bool)
+	for _, f := range front {
+		dev += math.Abs(math.Sqrt(f[0]*f[0]+f[1]*f[1]+f[2]*f[2]) - 1)
+		best, j := math.Inf(1), 0
+		for k, w := range reference {
+			if d := lineDistance(f, w); d < best {
+				best, j = d, k
+			}
+		}
+		covered[j] = true
+	}
+	if dev /= float64(len(front)); dev > 0.05 {
+		t.Errorf("%s: front is %v from the unit sphere on average; want at most 0.05", name, dev)
+	}
+	if reference != nil && len(covered) < len(reference)*2/3 {
+		t.Errorf("%s: front covers %d of %d reference directions; want at least 2/3", name, len(covered), len(reference))
+	}
+}
+
+func TestNSGA3(t *testing.T) {
+	ref := DasDennis(3, 6)
+	ga := NewGANSGA3(moeaParameter(), dtlz2, ref)
+	ga.Init(28, NewFloatGenome(make([]float64, 7), nil, 1, 0))
+	ga.Optimize(200)
+	if ga.Generation() != 200 || len(ga.Population()) != 28 {
+		t.Errorf("generation %d with %d genomes; want 200 and 28", ga.Generation(), len(ga.Population()))
+	}
+	checkDTLZ2(t, "NSGA-III", ga.ParetoObjectives(), ref)
+}
+
+func TestMOEAD(t *testing.T) {
+	ref := DasDennis(3, 6)
+	for _, d := range []GADecomposition{GATchebycheff, GAPBI} {
+		ga := NewGAMOEAD(moeaParameter(), dtlz2, ref)
+		ga.Decomposition = d
+		ga.Neighbours = 6
+		ga.Replacements = 2
+		ga.Init(NewFloatGenome(make([]float64, 7), nil, 1, 0))
+		ga.Optimize(200)
+		if len(ga.Population()) != len(ref) {
+			t.Errorf("decomposition %d: %d genomes; want one for each of %d weights", d, len(ga.Population()), len(ref))
+		}
+		if d == GAPBI {
+			checkDTLZ2(t, "MOEA/D PBI", ga.ParetoObjectives(), ref)
+			continue
+		}
+		// The Tchebycheff optimum of weight w does not lie in direction w
+		// and neighbouring weights often share one, only check there are
+		// many distinct optima.
+		front := ga.ParetoObjectives()
+		checkDTLZ2(t, "MOEA/D Tchebycheff", front, nil)
+		if len(front) < len(ref)/2 {
+			t.Errorf("MOEA/D Tchebycheff: front of %d genomes for %d weights; want at least half", len(front), len(ref))
+		}
+	}
+
+	ga := NewGAMOEAD(moeaParameter(), zdt1, DasDennis(2, 59))
+	ga.Init(NewFloatGenome(make([]float64, 8), nil, 1, 0))
+	ga.Optimize(200)
+	checkZDT1(t, "MOEA/D", ga.ParetoObjectives())
+}
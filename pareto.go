@@ -0,0 +1,247 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Pareto dominance for multi-objective optimization, where every objective
+is minimized and a genome is better than another only if it is no worse in
+any objective and better in at least one.
+*/
+
+package ga
+
+import (
+	"math"
+	"sort"
+)
+
+// Optional interface for genomes with several objectives.
+type GAObjectiveGenome interface {
+	GAGenome
+	//Objective vector, every objective is minimized
+	Objectives() []float64
+}
+
+// Optional interface for genomes with constraints.
+type GAConstrainedGenome interface {
+	GAGenome
+	//Total amount by which the constraints are violated, 0 if none is
+	Violation() float64
+}
+
+// Dominates reports whether objective vector a Pareto dominates b.
+func Dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// A genome with its objectives, for the multi-objective engines.
+type gaIndividual struct {
+	g         GAGenome
+	obj       []float64
+	violation float64
+	rank      int
+	crowding  float64
+	fitness   float64
+}
+
+// constrainedDominates reports whether a dominates b: a feasible genome
+// dominates an infeasible one, of two infeasible genomes the one violating
+// the constraints less dominates, and two feasible genomes are compared by
+// Pareto dominance.
+func constrainedDominates(a, b *gaIndividual) bool {
+	switch {
+	case a.violation <= 0 && b.violation > 0:
+		return true
+	case a.violation > 0 && b.violation <= 0:
+		return false
+	case a.violation > 0:
+		return a.violation < b.violation
+	}
+	return Dominates(a.obj, b.obj)
+}
+
+// nonDominatedSort sorts pop into fronts by constrained domination and sets
+// the rank of every individual to the index of its front.
+func nonDominatedSort(pop []*gaIndividual) [][]*gaIndividual {
+	n := len(pop)
+	dominated := make([][]int, n)
+	count := make([]int, n)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case constrainedDominates(pop[i], pop[j]):
+				dominated[i] = append(dominated[i], j)
+				count[j]++
+			case constrainedDominates(pop[j], pop[i]):
+				dominated[j] = append(dominated[j], i)
+				count[i]++
+			}
+		}
+	}
+	var front []int
+	for i := 0; i < n; i++ {
+		if count[i] == 0 {
+			front = append(front, i)
+		}
+	}
+	var fronts [][]*gaIndividual
+	for rank := 0; len(front) > 0; rank++ {
+		f := make([]*gaIndividual, len(front))
+		var next []int
+		for k, i := range front {
+			pop[i].rank = rank
+			f[k] = pop[i]
+			for _, j := range dominated[i] {
+				if count[j]--; count[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, f)
+		front = next
+	}
+	return fronts
+}
+
+// crowdingDistance sets the crowding distance of every individual of the
+// front, the sum over the objectives of the normalized distance between its
+// neighbours. The extremes of every objective get an infinite distance.
+func crowdingDistance(front []*gaIndividual) {
+	for _, x := range front {
+		x.crowding = 0
+	}
+	if len(front) == 0 {
+		return
+	}
+	sorted := make([]*gaIndividual, len(front))
+	copy(sorted, front)
+	for m := range front[0].obj {
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].obj[m] < sorted[j].obj[m] })
+		lo, hi := sorted[0].obj[m], sorted[len(sorted)-1].obj[m]
+		sorted[0].crowding = math.Inf(1)
+		sorted[len(sorted)-1].crowding = math.Inf(1)
+		if hi == lo {
+			continue
+		}
+		for i := 1; i < len(sorted)-1; i++ {
+			sorted[i].crowding += (sorted[i+1].obj[m] - sorted[i-1].obj[m]) / (hi - lo)
+		}
+	}
+}
+
+// genomesOf returns the genomes of pop.
+func genomesOf(pop []*gaIndividual) GAGenomes {
+	g := make(GAGenomes, len(pop))
+	for i, x := range pop {
+		g[i] = x.g
+	}
+	return g
+}
+
+// individual returns g with its objectives and constraint violation, from
+// its own methods if it implements GAObjectiveGenome or GAConstrainedGenome
+// and from objectives and violation otherwise.
+func individual(g GAGenome, objectives func(GAGenome) []float64, violation func(GAGenome) float64) *gaIndividual {
+	x := &gaIndividual{g: g}
+	if o, ok := g.(GAObjectiveGenome); ok {
+		x.obj = o.Objectives()
+	} else if objectives != nil {
+		x.obj = objectives(g)
+	} else {
+		panic("No objectives for genome")
+	}
+	if c, ok := g.(GAConstrainedGenome); ok {
+		x.violation = c.Violation()
+	} else if violation != nil {
+		x.violation = violation(g)
+	}
+	return x
+}
+
+func individuals(pop GAGenomes, objectives func(GAGenome) []float64, violation func(GAGenome) float64) []*gaIndividual {
+	x := make([]*gaIndividual, len(pop))
+	for i, g := range pop {
+		x[i] = individual(g, objectives, violation)
+	}
+	return x
+}
+
+// offspring returns the two children of a and b made by the Breeder and
+// Mutator of p, or copies of a and b if neither is used.
+func offspring(p *GAParameter, a, b GAGenome) GAGenomes {
+	var c GAGenomes
+	if p.Breeder != nil && p.PBreed > rng.Float64() {
+		x, y := p.Breeder.Breed(a, b)
+		c = GAGenomes{x, y}
+	} else {
+		c = GAGenomes{a.Copy(), b.Copy()}
+	}
+	for i := range c {
+		if p.Mutator != nil && p.PMutate > rng.Float64() {
+			c[i] = p.Mutator.Mutate(c[i])
+		}
+	}
+	return c
+}
+
+// paretoFront returns the individuals of pop no other individual dominates.
+func paretoFront(pop []*gaIndividual) []*gaIndividual {
+	var front []*gaIndividual
+	for _, a := range pop {
+		dominated := false
+		for _, b := range pop {
+			if b != a && constrainedDominates(b, a) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, a)
+		}
+	}
+	return front
+}
+
+func objectivesOf(pop []*gaIndividual) [][]float64 {
+	obj := make([][]float64, len(pop))
+	for i, x := range pop {
+		obj[i] = x.obj
+	}
+	return obj
+}
+
+// DasDennis returns the points of the unit simplex in m dimensions whose
+// coordinates are multiples of 1/p, the reference points and weight vectors
+// of NSGA-III and MOEA/D. There are (p+m-1)!/(p!(m-1)!) of them.
+func DasDennis(m, p int) [][]float64 {
+	var points [][]float64
+	point := make([]int, m)
+	var fill func(i, left int)
+	fill = func(i, left int) {
+		if i == m-1 {
+			point[i] = left
+			w := make([]float64, m)
+			for j, k := range point {
+				w[j] = float64(k) / float64(p)
+			}
+			points = append(points, w)
+			return
+		}
+		for k := 0; k <= left; k++ {
+			point[i] = k
+			fill(i+1, left-k)
+		}
+	}
+	if m > 0 && p > 0 {
+		fill(0, p)
+	}
+	return points
+}
@@ -0,0 +1,71 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func vectors(obj ...[]float64) []*gaIndividual {
+	pop := make([]*gaIndividual, len(obj))
+	for i, o := range obj {
+		pop[i] = &gaIndividual{obj: o}
+	}
+	return pop
+}
+
+func TestDominates(t *testing.T) {
+	tests := []struct {
+		a, b []float64
+		want bool
+	}{
+		{[]float64{1, 2}, []float64{2, 3}, true},
+		{[]float64{1, 3}, []float64{2, 3}, true},
+		{[]float64{2, 3}, []float64{2, 3}, false},
+		{[]float64{1, 4}, []float64{2, 3}, false},
+	}
+	for _, tt := range tests {
+		if got := Dominates(tt.a, tt.b); got != tt.want {
+			t.Errorf("Dominates(%v, %v) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	pop := vectors(
+		[]float64{1, 5}, []float64{2, 2}, []float64{5, 1}, // front 0
+		[]float64{3, 3}, []float64{2, 6}, // front 1
+		[]float64{4, 4}, // front 2
+	)
+	fronts := nonDominatedSort(pop)
+	if len(fronts) != 3 || len(fronts[0]) != 3 || len(fronts[1]) != 2 || len(fronts[2]) != 1 {
+		t.Fatalf("fronts of sizes %d; want 3, 2 and 1", len(fronts))
+	}
+	for i, want := range []int{0, 0, 0, 1, 1, 2} {
+		if pop[i].rank != want {
+			t.Errorf("%v in front %d; want %d", pop[i].obj, pop[i].rank, want)
+		}
+	}
+
+	crowdingDistance(fronts[0])
+	if !math.IsInf(pop[0].crowding, 1) || !math.IsInf(pop[2].crowding, 1) {
+		t.Errorf("extremes have crowding %v and %v; want infinite", pop[0].crowding, pop[2].crowding)
+	}
+	// (5-1)/4 + (5-1)/4
+	if pop[1].crowding != 2 {
+		t.Errorf("middle crowding distance = %v; want 2", pop[1].crowding)
+	}
+
+	// A feasible genome beats an infeasible one, less violation beats more.
+	pop = vectors([]float64{1, 1}, []float64{5, 5}, []float64{0, 0}, []float64{0, 1})
+	pop[0].violation, pop[2].violation, pop[3].violation = 2, 1, 0.5
+	nonDominatedSort(pop)
+	if pop[1].rank != 0 {
+		t.Errorf("feasible genome in front %d; want 0", pop[1].rank)
+	}
+	if !(pop[3].rank < pop[2].rank && pop[2].rank < pop[0].rank) {
+		t.Errorf("infeasible genomes in fronts %d, %d, %d; want ordered by violation", pop[3].rank, pop[2].rank, pop[0].rank)
+	}
+	if f := paretoFront(pop); len(f) != 1 || f[0] != pop[1] {
+		t.Errorf("paretoFront() = %v; want only the feasible genome", objectivesOf(f))
+	}
+}
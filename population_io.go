@@ -0,0 +1,295 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Population import and export as CSV or JSON Lines.
+
+CSV rows hold generation, rank, score and then one column per gene, after a
+header row naming the columns gene0, gene1 and so on. JSON Lines records
+hold the same fields with the genome in the JSON form of genome_encoding.go.
+Imports also accept CSV without header, where every column is a gene, and
+JSON Lines of bare genomes.
+*/
+
+package ga
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type GAFormat int
+
+const (
+	GACSV GAFormat = iota
+	GAJSONLines
+)
+
+func (f GAFormat) String() string {
+	switch f {
+	case GACSV:
+		return "CSV"
+	case GAJSONLines:
+		return "JSON Lines"
+	}
+	return fmt.Sprintf("GAFormat(%d)", int(f))
+}
+
+type gaExportRecord struct {
+	Generation int             `json:"generation"`
+	Rank       int             `json:"rank"`
+	Score      float64         `json:"score"`
+	Genome     json.RawMessage `json:"genome"`
+}
+
+// ExportPopulation writes every genome of the population, best first, with
+// its score, rank and the current generation to w.
+func (ga *GA) ExportPopulation(w io.Writer, format GAFormat) error {
+	return exportGenomes(w, format, ga.pop, ga.generation)
+}
+
+func exportGenomes(w io.Writer, format GAFormat, pop GAGenomes, generation int) error {
+	sorted := make(GAGenomes, len(pop))
+	copy(sorted, pop)
+	sort.Sort(sorted)
+	switch format {
+	case GACSV:
+		cw := csv.NewWriter(w)
+		l := 0
+		for _, g := range sorted {
+			if g.Len() > l {
+				l = g.Len()
+			}
+		}
+		header := []string{"generation", "rank", "score"}
+		for i := 0; i < l; i++ {
+			header = append(header, "gene"+strconv.Itoa(i))
+		}
+		cw.Write(header)
+		for rank, g := range sorted {
+			genes, err := geneStrings(g)
+			if err != nil {
+				return err
+			}
+			row := []string{strconv.Itoa(generation), strconv.Itoa(rank),
+				strconv.FormatFloat(g.Score(), 'g', -1, 64)}
+			cw.Write(append(row, genes...))
+		}
+		cw.Flush()
+		return cw.Error()
+	case GAJSONLines:
+		enc := json.NewEncoder(w)
+		for rank, g := range sorted {
+			data, err := json.Marshal(g)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(gaExportRecord{generation, rank, g.Score(), data}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("ga: unknown format %v", format)
+}
+
+// readGenes reads the genes of every genome in r, as strings for CSV and as
+// JSON arrays for JSON Lines.
+func readGenes(r io.Reader, format GAFormat) (rows [][]string, arrays []json.RawMessage, err error) {
+	switch format {
+	case GACSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		records, err := cr.ReadAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil, nil
+		}
+		// Without a header every column is a gene.
+		first := 0
+		if _, err := strconv.ParseFloat(records[0][0], 64); err == nil || isBool(records[0][0]) {
+			return records, nil, nil
+		}
+		for first < len(records[0]) && records[0][first] != "gene0" {
+			first++
+		}
+		if first == len(records[0]) {
+			return nil, nil, fmt.Errorf("ga: CSV header %q has no gene0 column", records[0])
+		}
+		for _, rec := range records[1:] {
+			if first < len(rec) {
+				rows = append(rows, rec[first:])
+			} else {
+				rows = append(rows, nil)
+			}
+		}
+		return rows, nil, nil
+	case GAJSONLines:
+		s := bufio.NewScanner(r)
+		s.Buffer(nil, 64*1024*1024)
+		for s.Scan() {
+			line := bytes.TrimSpace(s.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var rec struct {
+				Genome *struct {
+					Gene json.RawMessage `json:"gene"`
+				} `json:"genome"`
+				Gene json.RawMessage `json:"gene"`
+			}
+			if err := json.Unmarshal(line, &rec); err != nil {
+				return nil, nil, err
+			}
+			gene := rec.Gene
+			if rec.Genome != nil {
+				gene = rec.Genome.Gene
+			}
+			if gene == nil {
+				return nil, nil, fmt.Errorf("ga: no genes in %q", line)
+			}
+			arrays = append(arrays, gene)
+		}
+		return nil, arrays, s.Err()
+	}
+	return nil, nil, fmt.Errorf("ga: unknown format %v", format)
+}
+
+func isBool(s string) bool {
+	_, err := strconv.ParseBool(s)
+	return err == nil
+}
+
+// geneStrings returns the genes of a built-in genome as strings.
+func geneStrings(g GAGenome) ([]string, error) {
+	var s []string
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		for _, c := range g.Gene {
+			if c {
+				s = append(s, "1")
+			} else {
+				s = append(s, "0")
+			}
+		}
+	case *GAIntGenome:
+		for _, c := range g.Gene {
+			s = append(s, strconv.Itoa(c))
+		}
+	case *GAOrderedIntGenome:
+		for _, c := range g.Gene {
+			s = append(s, strconv.Itoa(c))
+		}
+	case *GAFloatGenome:
+		for _, c := range g.Gene {
+			s = append(s, strconv.FormatFloat(c, 'g', -1, 64))
+		}
+	case *GAFloat32Genome:
+		for _, c := range g.Gene {
+			s = append(s, strconv.FormatFloat(float64(c), 'g', -1, 32))
+		}
+	default:
+		return nil, fmt.Errorf("ga: can not export genome of type %T", g)
+	}
+	return s, nil
+}
+
+// setGeneStrings replaces the genes of a built-in genome with the parsed s.
+func setGeneStrings(g GAGenome, s []string) error {
+	var err error
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		g.Gene = make([]bool, len(s))
+		for i, c := range s {
+			if g.Gene[i], err = strconv.ParseBool(strings.TrimSpace(c)); err != nil {
+				return err
+			}
+		}
+	case *GAIntGenome:
+		g.Gene = make([]int, len(s))
+		for i, c := range s {
+			if g.Gene[i], err = strconv.Atoi(strings.TrimSpace(c)); err != nil {
+				return err
+			}
+		}
+	case *GAOrderedIntGenome:
+		g.Gene = make([]int, len(s))
+		for i, c := range s {
+			if g.Gene[i], err = strconv.Atoi(strings.TrimSpace(c)); err != nil {
+				return err
+			}
+		}
+	case *GAFloatGenome:
+		g.Gene = make([]float64, len(s))
+		for i, c := range s {
+			if g.Gene[i], err = strconv.ParseFloat(strings.TrimSpace(c), 64); err != nil {
+				return err
+			}
+		}
+	case *GAFloat32Genome:
+		g.Gene = make([]float32, len(s))
+		for i, c := range s {
+			var f float64
+			if f, err = strconv.ParseFloat(strings.TrimSpace(c), 32); err != nil {
+				return err
+			}
+			g.Gene[i] = float32(f)
+		}
+	default:
+		return fmt.Errorf("ga: can not import genome of type %T", g)
+	}
+	g.Reset()
+	return nil
+}
+
+// geneJSON returns the genes of a built-in genome as a JSON array.
+func geneJSON(g GAGenome) (json.RawMessage, error) {
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		return json.Marshal(g.Gene)
+	case *GAIntGenome:
+		return json.Marshal(g.Gene)
+	case *GAOrderedIntGenome:
+		return json.Marshal(g.Gene)
+	case *GAFloatGenome:
+		return json.Marshal(g.Gene)
+	case *GAFloat32Genome:
+		return json.Marshal(g.Gene)
+	}
+	return nil, fmt.Errorf("ga: can not export genome of type %T", g)
+}
+
+// setGeneJSON replaces the genes of a built-in genome with the JSON array.
+func setGeneJSON(g GAGenome, data json.RawMessage) error {
+	var err error
+	switch g := g.(type) {
+	case *GAFixedBitstringGenome:
+		g.Gene = nil
+		err = json.Unmarshal(data, &g.Gene)
+	case *GAIntGenome:
+		g.Gene = nil
+		err = json.Unmarshal(data, &g.Gene)
+	case *GAOrderedIntGenome:
+		g.Gene = nil
+		err = json.Unmarshal(data, &g.Gene)
+	case *GAFloatGenome:
+		g.Gene = nil
+		err = json.Unmarshal(data, &g.Gene)
+	case *GAFloat32Genome:
+		g.Gene = nil
+		err = json.Unmarshal(data, &g.Gene)
+	default:
+		return fmt.Errorf("ga: can not import genome of type %T", g)
+	}
+	g.Reset()
+	return err
+}
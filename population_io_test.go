@@ -0,0 +1,82 @@
+package ga
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExportImportPopulation(t *testing.T) {
+	for _, format := range []GAFormat{GACSV, GAJSONLines} {
+		ga := NewGA(GAParameter{Initializer: new(GARandomInitializer)})
+		ga.Init(10, NewIntGenome(make([]int, 4), firstGene, 0, 9))
+		var b bytes.Buffer
+		if err := ga.ExportPopulation(&b, format); err != nil {
+			t.Fatalf("ExportPopulation(%v) = %v", format, err)
+		}
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if format == GACSV && lines[0] != "generation,rank,score,gene0,gene1,gene2,gene3" {
+			t.Errorf("CSV header = %q", lines[0])
+		}
+
+		imp, err := NewGAImportInitializer(&b, format, NewIntGenome(make([]int, 4), firstGene, 0, 9))
+		if err != nil {
+			t.Fatalf("NewGAImportInitializer(%v) = %v", format, err)
+		}
+		if imp.Len() != 10 {
+			t.Errorf("%v: imported %d genomes; want 10", format, imp.Len())
+		}
+		pop := imp.InitPop(NewIntGenome(make([]int, 4), firstGene, 0, 9), 12)
+		for i := 0; i < 10; i++ {
+			if got, want := pop[i].String(), ga.pop[i].String(); got != want {
+				t.Errorf("%v: imported genome %d = %v; want %v", format, i, got, want)
+			}
+			if got, want := pop[i].Score(), ga.pop[i].Score(); got != want {
+				t.Errorf("%v: imported genome %d scores %v; want %v", format, i, got, want)
+			}
+		}
+		for _, g := range pop[10:] {
+			if g.Len() != 4 {
+				t.Errorf("%v: random genome %v; want 4 genes", format, g)
+			}
+		}
+	}
+}
+
+func TestImportPlain(t *testing.T) {
+	tests := []struct {
+		format GAFormat
+		data   string
+	}{
+		{GACSV, "1,0,1\n0,0,1\n"},
+		{GAJSONLines, "{\"gene\":[true,false,true]}\n\n{\"gene\":[false,false,true]}\n"},
+	}
+	for _, test := range tests {
+		imp, err := NewGAImportInitializer(strings.NewReader(test.data), test.format, NewFixedBitstringGenome(make([]bool, 3), bitsScore))
+		if err != nil {
+			t.Fatalf("NewGAImportInitializer(%v) = %v", test.format, err)
+		}
+		pop := imp.InitPop(NewFixedBitstringGenome(make([]bool, 3), bitsScore), 2)
+		if pop[0].Score() != 2 || pop[1].Score() != 1 {
+			t.Errorf("%v: imported %v; want [true false true] [false false true]", test.format, pop)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		format GAFormat
+		data   string
+	}{
+		{GACSV, "generation,rank,score\n0,0,1\n"},
+		{GACSV, "1,0,1\n0,1\n"},
+		{GACSV, "1,0,1\n0,x,1\n"},
+		{GAJSONLines, "{\"gene\":[true,false]}\n"},
+	}
+	for _, test := range tests {
+		_, err := NewGAImportInitializer(strings.NewReader(test.data), test.format, NewFixedBitstringGenome(make([]bool, 3), bitsScore))
+		if err == nil {
+			t.Errorf("NewGAImportInitializer(%v, %q) succeeded; want an error", test.format, test.data)
+		}
+	}
+}
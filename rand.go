@@ -0,0 +1,77 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Random number generator used by all operators. Unlike the top level
+functions of math/rand its state can be saved in a checkpoint, so a
+resumed run continues exactly where it stopped.
+*/
+
+package ga
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	rngSource = newGASource(time.Now().UnixNano())
+	rng       = rand.New(rngSource)
+)
+
+// Seed seeds the random number generator of the package, for reproducible
+// runs.
+func Seed(seed int64) {
+	rng.Seed(seed)
+}
+
+// splitmix64 generator, safe for concurrent use.
+type gaSource struct {
+	mu    sync.Mutex
+	state uint64
+}
+
+func newGASource(seed int64) *gaSource {
+	s := new(gaSource)
+	s.Seed(seed)
+	return s
+}
+
+func (s *gaSource) Seed(seed int64) {
+	s.mu.Lock()
+	s.state = uint64(seed)
+	s.mu.Unlock()
+}
+
+func (s *gaSource) Uint64() uint64 {
+	s.mu.Lock()
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	s.mu.Unlock()
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *gaSource) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+func (s *gaSource) MarshalBinary() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, s.state)
+	return b, nil
+}
+
+func (s *gaSource) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return errors.New("ga: invalid random generator state")
+	}
+	s.mu.Lock()
+	s.state = binary.BigEndian.Uint64(b)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+go-galib replacements, survivor selection that keeps the population size
+fixed. Crowding replacements let children only compete with similar
+genomes, which keeps several niches alive on multimodal problems.
+*/
+
+package ga
+
+type GAReplacement interface {
+	// Replace puts children in pop in place of genomes of pop, or drops
+	// them. parents are the genomes the children were made from, they may
+	// no longer be in pop.
+	Replace(pop GAGenomes, parents, children GAGenomes)
+	// String name of replacement
+	String() string
+}
+
+// Pairs every child with its most similar parent and lets the better of the
+// two survive.
+type GADeterministicCrowding struct {
+	// Distance between genomes, nil uses Distance
+	Distance GADistance
+}
+
+func (r *GADeterministicCrowding) Replace(pop GAGenomes, parents, children GAGenomes) {
+	survivors := make(map[GAGenome]GAGenome)
+	for _, f := range pairFamily(r.Distance, parents, children) {
+		p := survivor(survivors, f.parent)
+		if f.child.Score() < p.Score() {
+			replace(pop, p, f.child)
+			survivors[f.parent] = f.child
+		}
+	}
+}
+
+func (r *GADeterministicCrowding) String() string { return "GADeterministicCrowding" }
+
+// Pairs every child with its most similar parent and lets the child survive
+// with chance Score(parent) / (Score(parent) + Score(child)), so the better
+// genome is more likely to survive but the worse one still can. Scores must
+// not be negative.
+type GAProbabilisticCrowding struct {
+	// Distance between genomes, nil uses Distance
+	Distance GADistance
+}
+
+func (r *GAProbabilisticCrowding) Replace(pop GAGenomes, parents, children GAGenomes) {
+	survivors := make(map[GAGenome]GAGenome)
+	for _, f := range pairFamily(r.Distance, parents, children) {
+		parent := survivor(survivors, f.parent)
+		sp, sc := parent.Score(), f.child.Score()
+		p := 0.5
+		if sp+sc > 0 {
+			p = sp / (sp + sc)
+		}
+		if p > rng.Float64() {
+			replace(pop, parent, f.child)
+			survivors[f.parent] = f.child
+		}
+	}
+}
+
+func (r *GAProbabilisticCrowding) String() string { return "GAProbabilisticCrowding" }
+
+// Restricted tournament selection. Every child is compared with the most
+// similar of Window random genomes of the population and replaces it if the
+// child is better.
+type GARestrictedTournament struct {
+	Window int
+	// Distance between genomes, nil uses Distance
+	Distance GADistance
+}
+
+func NewGARestrictedTournament(window int) *GARestrictedTournament {
+	return &GARestrictedTournament{Window: window}
+}
+
+func (r *GARestrictedTournament) Replace(pop GAGenomes, parents, children GAGenomes) {
+	if len(pop) == 0 {
+		return
+	}
+	dist := r.Distance
+	if dist == nil {
+		dist = Distance
+	}
+	w := r.Window
+	if w < 1 {
+		w = 1
+	}
+	for _, c := range children {
+		best, bestd := -1, 0.0
+		for i := 0; i < w; i++ {
+			j := rng.Intn(len(pop))
+			if d := dist(c, pop[j]); best < 0 || d < bestd {
+				best, bestd = j, d
+			}
+		}
+		if c.Score() < pop[best].Score() {
+			pop[best] = c
+		}
+	}
+}
+
+func (r *GARestrictedTournament) String() string { return "GARestrictedTournament" }
+
+type family struct {
+	parent, child GAGenome
+}
+
+// pairFamily pairs children with parents. With two of each the pairing with
+// the smallest total distance is used, otherwise every child is paired with
+// its closest parent.
+func pairFamily(dist GADistance, parents, children GAGenomes) []family {
+	if dist == nil {
+		dist = Distance
+	}
+	if len(parents) == 2 && len(children) == 2 {
+		p1, p2, c1, c2 := parents[0], parents[1], children[0], children[1]
+		if dist(p1, c1)+dist(p2, c2) <= dist(p1, c2)+dist(p2, c1) {
+			return []family{{p1, c1}, {p2, c2}}
+		}
+		return []family{{p1, c2}, {p2, c1}}
+	}
+	f := make([]family, 0, len(children))
+	for _, c := range children {
+		best, bestd := -1, 0.0
+		for i, p := range parents {
+			if d := dist(p, c); best < 0 || d < bestd {
+				best, bestd = i, d
+			}
+		}
+		if best >= 0 {
+			f = append(f, family{parents[best], c})
+		}
+	}
+	return f
+}
+
+// survivor returns the genome that took the place of parent p, or p. A
+// parent picked twice, or closest to two children, is competed for by its
+// second child against the winner of the first.
+func survivor(survivors map[GAGenome]GAGenome, p GAGenome) GAGenome {
+	if s, ok := survivors[p]; ok {
+		return s
+	}
+	return p
+}
+
+// replace puts n in place of o in pop, if o is still there.
+func replace(pop GAGenomes, o, n GAGenome) {
+	for i, g := range pop {
+		if g == o {
+			pop[i] = n
+			return
+		}
+	}
+}
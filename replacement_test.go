@@ -0,0 +1,109 @@
+package ga
+
+import (
+	"testing"
+)
+
+func bitsFrom(s string) *GAFixedBitstringGenome {
+	gene := make([]bool, len(s))
+	for i, c := range s {
+		gene[i] = c == '1'
+	}
+	return NewFixedBitstringGenome(gene, func(g *GAFixedBitstringGenome) float64 {
+		return float64(countTrue(g.Gene))
+	})
+}
+
+func TestDeterministicCrowding(t *testing.T) {
+	p1, p2 := bitsFrom("000011"), bitsFrom("111111")
+	// c1 is closest to p2 and better, c2 is closest to p1 and worse.
+	c1, c2 := bitsFrom("111110"), bitsFrom("000111")
+	pop := GAGenomes{p1, p2, bitsFrom("101010")}
+	r := &GADeterministicCrowding{}
+	r.Replace(pop, GAGenomes{p1, p2}, GAGenomes{c1, c2})
+	if pop[0] != p1 || pop[1] != c1 {
+		t.Errorf("population after Replace = %v; want [%v %v ...]", pop, p1, c1)
+	}
+	// p2 is no longer in the population, its child is dropped.
+	r.Replace(pop, GAGenomes{p2}, GAGenomes{bitsFrom("000000")})
+	if pop[0] != p1 || pop[1] != c1 {
+		t.Errorf("population after Replace of missing parent = %v", pop)
+	}
+}
+
+// Tests that a parent selected twice is competed for by its second child
+// against the winner of the first instead of dropping the second child.
+func TestCrowdingSameParent(t *testing.T) {
+	p := bitsFrom("111111")
+	c1, c2 := bitsFrom("011111"), bitsFrom("000111")
+	pop := GAGenomes{p}
+	(&GADeterministicCrowding{}).Replace(pop, GAGenomes{p, p}, GAGenomes{c1, c2})
+	if pop[0] != c2 {
+		t.Errorf("population after Replace = %v; want [%v]", pop, c2)
+	}
+	pop = GAGenomes{p}
+	(&GADeterministicCrowding{}).Replace(pop, GAGenomes{p, p}, GAGenomes{c2, c1})
+	if pop[0] != c2 {
+		t.Errorf("population after Replace with the better child first = %v; want [%v]", pop, c2)
+	}
+}
+
+func TestProbabilisticCrowding(t *testing.T) {
+	wins := 0
+	for i := 0; i < 10000; i++ {
+		p, c := bitsFrom("111000"), bitsFrom("100000")
+		pop := GAGenomes{p}
+		(&GAProbabilisticCrowding{}).Replace(pop, GAGenomes{p}, GAGenomes{c})
+		if pop[0] == c {
+			wins++
+		}
+	}
+	// The child scores 1 against the parent's 3, so it wins 3 in 4 times.
+	if wins < 7200 || wins > 7800 {
+		t.Errorf("child replaced parent %d times in 10000; want about 7500", wins)
+	}
+}
+
+func TestRestrictedTournament(t *testing.T) {
+	pop := GAGenomes{bitsFrom("000001"), bitsFrom("111111")}
+	r := NewGARestrictedTournament(20)
+	r.Replace(pop, nil, GAGenomes{bitsFrom("110111"), bitsFrom("000011")})
+	if got := pop[1].String(); got != bitsFrom("110111").String() {
+		t.Errorf("pop[1] = %v; want the similar, better child", got)
+	}
+	if got := pop[0].String(); got != bitsFrom("000001").String() {
+		t.Errorf("pop[0] = %v; want the worse child dropped", got)
+	}
+}
+
+// Tests that a GA with crowding keeps a fixed population size and does not
+// lose its best genome.
+func TestGAReplacement(t *testing.T) {
+	for _, r := range []GAReplacement{
+		&GADeterministicCrowding{},
+		&GAProbabilisticCrowding{},
+		NewGARestrictedTournament(5),
+	} {
+		ga := NewGA(GAParameter{
+			Initializer: new(GARandomInitializer),
+			Selector:    NewGATournamentSelector(0.7, 3),
+			Breeder:     new(GA2PointBreeder),
+			Mutator:     NewGABitFlipMutator(0),
+			PMutate:     0.3,
+			PBreed:      0.7,
+			Elite:       1,
+			Replacement: r})
+		ga.Init(30, bitsFrom("0000000000000000"))
+		best := ga.Best().Score()
+		for i := 0; i < 30; i++ {
+			ga.Optimize(1)
+			if len(ga.pop) != 30 {
+				t.Fatalf("%s: population size = %d; want 30", r, len(ga.pop))
+			}
+			if s := ga.Best().Score(); s > best {
+				t.Fatalf("%s: best score got worse, %v > %v", r, s, best)
+			}
+			best = ga.Best().Score()
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+go-galib schedules, for parameters that change during a run.
+*/
+
+package ga
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+)
+
+type GASchedule interface {
+	// Value of the parameter in generation gen, best is the best score of
+	// the previous generation.
+	Value(gen int, best float64) float64
+	// String name of schedule
+	String() string
+}
+
+// Moves linearly from From to To over Generations generations and stays at To
+// after that.
+type GALinearSchedule struct {
+	From, To    float64
+	Generations int
+}
+
+func (s *GALinearSchedule) Value(gen int, best float64) float64 {
+	if gen >= s.Generations {
+		return s.To
+	}
+	return s.From + (s.To-s.From)*float64(gen)/float64(s.Generations)
+}
+
+func (s *GALinearSchedule) String() string { return "GALinearSchedule" }
+
+// Starts at Start and is multiplied by Rate every generation, but never goes
+// past Limit if Limit is set.
+type GAExponentialSchedule struct {
+	Start, Rate, Limit float64
+}
+
+func (s *GAExponentialSchedule) Value(gen int, best float64) float64 {
+	v := s.Start * math.Pow(s.Rate, float64(gen))
+	if s.Limit != 0 {
+		if s.Rate < 1 && v < s.Limit || s.Rate > 1 && v > s.Limit {
+			return s.Limit
+		}
+	}
+	return v
+}
+
+func (s *GAExponentialSchedule) String() string { return "GAExponentialSchedule" }
+
+// Starts at Start and is multiplied by Factor every Every generations.
+type GAStepSchedule struct {
+	Start, Factor float64
+	Every         int
+}
+
+func (s *GAStepSchedule) Value(gen int, best float64) float64 {
+	if s.Every <= 0 {
+		return s.Start
+	}
+	return s.Start * math.Pow(s.Factor, float64(gen/s.Every))
+}
+
+func (s *GAStepSchedule) String() string { return "GAStepSchedule" }
+
+// Cosine annealing from Max down to Min over Period generations. With Restart
+// the schedule jumps back to Max after every period, otherwise it stays at Min.
+type GACosineSchedule struct {
+	Max, Min float64
+	Period   int
+	Restart  bool
+}
+
+func (s *GACosineSchedule) Value(gen int, best float64) float64 {
+	if s.Period <= 0 {
+		return s.Min
+	}
+	if gen >= s.Period {
+		if !s.Restart {
+			return s.Min
+		}
+		gen %= s.Period
+	}
+	return s.Min + (s.Max-s.Min)*(1+math.Cos(math.Pi*float64(gen)/float64(s.Period)))/2
+}
+
+func (s *GACosineSchedule) String() string { return "GACosineSchedule" }
+
+// Stays at Base while the best score improves. Once it has not improved for
+// Patience generations the value is multiplied by Factor every generation,
+// never going past Limit if Limit is set, until the best score improves again.
+// Useful to raise the mutation rate when the population has converged.
+type GAStagnationSchedule struct {
+	Base, Factor, Limit float64
+	Patience            int
+
+	value   float64
+	best    float64
+	last    int
+	started bool
+}
+
+func NewGAStagnationSchedule(base, factor, limit float64, patience int) *GAStagnationSchedule {
+	return &GAStagnationSchedule{Base: base, Factor: factor, Limit: limit, Patience: patience}
+}
+
+func (s *GAStagnationSchedule) Value(gen int, best float64) float64 {
+	if !s.started || best < s.best {
+		s.started = true
+		s.best = best
+		s.last = gen
+		s.value = s.Base
+		return s.value
+	}
+	if gen-s.last >= s.Patience {
+		s.value *= s.Factor
+		if s.Limit != 0 {
+			if s.Factor > 1 && s.value > s.Limit || s.Factor < 1 && s.value < s.Limit {
+				s.value = s.Limit
+			}
+		}
+	}
+	return s.value
+}
+
+func (s *GAStagnationSchedule) String() string { return "GAStagnationSchedule" }
+
+type gaStagnationState struct {
+	Value, Best float64
+	Last        int
+	Started     bool
+}
+
+// MarshalBinary saves the progress of the schedule, for checkpoints.
+func (s *GAStagnationSchedule) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(gaStagnationState{s.value, s.best, s.last, s.started})
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary restores the progress saved by MarshalBinary.
+func (s *GAStagnationSchedule) UnmarshalBinary(data []byte) error {
+	var st gaStagnationState
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st); err != nil {
+		return err
+	}
+	s.value, s.best, s.last, s.started = st.Value, st.Best, st.Last, st.Started
+	return nil
+}
+
+// A schedule bound to the parameter it drives.
+type gaScheduled struct {
+	name     string
+	target   *float64
+	schedule GASchedule
+}
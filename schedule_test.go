@@ -0,0 +1,92 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSchedules(t *testing.T) {
+	tests := []struct {
+		s    GASchedule
+		gen  int
+		want float64
+	}{
+		{&GALinearSchedule{From: 1, To: 0, Generations: 10}, 0, 1},
+		{&GALinearSchedule{From: 1, To: 0, Generations: 10}, 5, 0.5},
+		{&GALinearSchedule{From: 1, To: 0, Generations: 10}, 20, 0},
+		{&GAExponentialSchedule{Start: 1, Rate: 0.5}, 3, 0.125},
+		{&GAExponentialSchedule{Start: 1, Rate: 0.5, Limit: 0.2}, 3, 0.2},
+		{&GAStepSchedule{Start: 1, Factor: 0.1, Every: 10}, 9, 1},
+		{&GAStepSchedule{Start: 1, Factor: 0.1, Every: 10}, 25, 0.01},
+		{&GACosineSchedule{Max: 1, Min: 0, Period: 10}, 0, 1},
+		{&GACosineSchedule{Max: 1, Min: 0, Period: 10}, 5, 0.5},
+		{&GACosineSchedule{Max: 1, Min: 0, Period: 10}, 15, 0},
+		{&GACosineSchedule{Max: 1, Min: 0, Period: 10, Restart: true}, 15, 0.5},
+	}
+	for _, test := range tests {
+		if got := test.s.Value(test.gen, 0); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s%+v.Value(%d) = %v; want %v", test.s, test.s, test.gen, got, test.want)
+		}
+	}
+}
+
+func TestStagnationSchedule(t *testing.T) {
+	s := NewGAStagnationSchedule(0.1, 2, 0.5, 2)
+	steps := []struct {
+		best, want float64
+	}{
+		{10, 0.1},
+		{9, 0.1},
+		{9, 0.1},
+		{9, 0.2},
+		{9, 0.4},
+		{9, 0.5},
+		{8, 0.1},
+	}
+	for gen, step := range steps {
+		if got := s.Value(gen, step.best); math.Abs(got-step.want) > 1e-9 {
+			t.Errorf("Value(%d, %v) = %v; want %v", gen, step.best, got, step.want)
+		}
+	}
+}
+
+// Tests that GA applies schedules every generation and records their values.
+func TestGASchedule(t *testing.T) {
+	m := NewGAGaussianMutator(1, 0)
+	ga := NewGA(GAParameter{
+		Initializer: new(GARandomInitializer),
+		Selector:    NewGATournamentSelector(0.7, 5),
+		Breeder:     new(GA2PointBreeder),
+		Mutator:     m,
+		PMutate:     0.5,
+		PBreed:      0.5})
+	ga.Schedule("PMutate", &ga.Parameter.PMutate, &GALinearSchedule{From: 1, To: 0.1, Generations: 9})
+	ga.Schedule("StdDev", &m.StdDev, &GAStepSchedule{Start: 1, Factor: 0.5, Every: 5})
+	ga.Init(20, NewFloatGenome(make([]float64, 5), sphere, 10, -10))
+	ga.Optimize(10)
+
+	if g := ga.Generation(); g != 10 {
+		t.Errorf("Generation() = %d; want 10", g)
+	}
+	h := ga.History()
+	if len(h) != 11 {
+		t.Fatalf("len(History()) = %d; want 11", len(h))
+	}
+	for i, s := range h[1:] {
+		if s.Generation != i+1 {
+			t.Errorf("History()[%d].Generation = %d; want %d", i+1, s.Generation, i+1)
+		}
+		if want := 1 - 0.1*float64(i); math.Abs(s.Parameters["PMutate"]-want) > 1e-9 {
+			t.Errorf("History()[%d] PMutate = %v; want %v", i+1, s.Parameters["PMutate"], want)
+		}
+		if s.Best > h[i].Best {
+			t.Errorf("History()[%d].Best = %v got worse than %v", i+1, s.Best, h[i].Best)
+		}
+	}
+	if got := ga.Stats().Parameters["StdDev"]; got != 0.5 {
+		t.Errorf("Stats() StdDev = %v; want 0.5", got)
+	}
+	if ga.Parameter.PMutate != 0.1 || m.StdDev != 0.5 {
+		t.Errorf("PMutate, StdDev = %v, %v; want 0.1, 0.5", ga.Parameter.PMutate, m.StdDev)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+Registry of score functions. Functions can not be saved, so genomes are
+saved with the name their score function was registered under and get the
+function back by name when they are loaded.
+*/
+
+package ga
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var scores = struct {
+	sync.RWMutex
+	byName map[string]interface{}
+	byFunc map[uintptr]string
+}{byName: make(map[string]interface{}), byFunc: make(map[uintptr]string)}
+
+// RegisterScore registers the score function fn of a genome under name, for
+// example RegisterScore("tsp", tsp) for a func(*GAOrderedIntGenome) float64.
+// Closures made by the same function literal can not be told apart, only one
+// of them can be registered.
+func RegisterScore(name string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		panic("RegisterScore needs a function")
+	}
+	scores.Lock()
+	defer scores.Unlock()
+	scores.byName[name] = fn
+	scores.byFunc[v.Pointer()] = name
+}
+
+// scoreName returns the name fn was registered under. A function that is not
+// registered is an error if strict is set, otherwise the name is empty.
+func scoreName(fn interface{}, strict bool) (string, error) {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.IsNil() {
+		return "", nil
+	}
+	scores.RLock()
+	defer scores.RUnlock()
+	name, ok := scores.byFunc[v.Pointer()]
+	if !ok && strict {
+		return "", fmt.Errorf("ga: score function %v is not registered", v.Type())
+	}
+	return name, nil
+}
+
+// scoreFunc sets *fn to the score function registered under name.
+func scoreFunc(name string, fn interface{}) error {
+	if name == "" {
+		return nil
+	}
+	scores.RLock()
+	f, ok := scores.byName[name]
+	scores.RUnlock()
+	if !ok {
+		return fmt.Errorf("ga: no score function registered as %q", name)
+	}
+	dst := reflect.ValueOf(fn).Elem()
+	v := reflect.ValueOf(f)
+	if !v.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("ga: score function %q is a %v, not a %v", name, v.Type(), dst.Type())
+	}
+	dst.Set(v)
+	return nil
+}
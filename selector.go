@@ -10,7 +10,6 @@ package ga
 
 import (
 	"math"
-	"math/rand"
 	"sort"
 )
 
@@ -46,11 +45,11 @@ func (s *GATournamentSelector) SelectOne(pop GAGenomes) GAGenome {
 	l := len(pop)
 	//fmt.Printf("Length = %d, Contestants = %d\n", l, len(g));
 	for i := 0; i < s.Contestants; i++ {
-		g[i] = pop[rand.Intn(l)]
+		g[i] = pop[rng.Intn(l)]
 	}
 	sort.Sort(g)
 	//fmt.Printf("%+v\n", g);
-	r := rand.Float64()
+	r := rng.Float64()
 	for i := 0; i < s.Contestants-1; i++ {
 		if s.PElite*math.Pow((float64(1)-s.PElite), float64(i+1)) < r {
 			return g[i]
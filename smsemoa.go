@@ -0,0 +1,117 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+SMS-EMOA, the S-metric selection EMOA of Beume, Naujoks and Emmerich. A
+steady-state algorithm: each step adds one child to the population and then
+drops, from the worst Pareto front, the genome contributing the least
+hypervolume, so the population moves towards the front of largest
+hypervolume.
+*/
+
+package ga
+
+import (
+	"math"
+)
+
+type GASMSEMOA struct {
+	// Initializer, Breeder, Mutator, PBreed and PMutate are used as by GA.
+	// Parents are picked at random, the Selector is not used.
+	Parameter GAParameter
+	// Objective vector of a genome, every objective is minimized. Only used
+	// for genomes that do not implement GAObjectiveGenome.
+	Objectives func(g GAGenome) []float64
+	// Constraint violation of a genome, 0 if it is feasible, nil if there
+	// are no constraints. Only used for genomes that do not implement
+	// GAConstrainedGenome.
+	Violation func(g GAGenome) float64
+	// Reference point of the hypervolume, nil uses the worst objectives of
+	// the worst front plus 1
+	Reference []float64
+
+	pop        []*gaIndividual
+	generation int
+}
+
+func NewGASMSEMOA(parameter GAParameter, objectives func(g GAGenome) []float64) *GASMSEMOA {
+	return &GASMSEMOA{Parameter: parameter, Objectives: objectives}
+}
+
+func (ga *GASMSEMOA) String() string { return "GASMSEMOA" }
+
+func (ga *GASMSEMOA) Init(popsize int, i GAGenome) {
+	ga.generation = 0
+	ga.pop = individuals(ga.Parameter.Initializer.InitPop(i, popsize), ga.Objectives, ga.Violation)
+}
+
+// Optimize runs gen generations, each as many steps as the population has
+// genomes.
+func (ga *GASMSEMOA) Optimize(gen int) {
+	for i := 0; i < gen; i++ {
+		for s := len(ga.pop); s > 0; s-- {
+			a, b := ga.pop[rng.Intn(len(ga.pop))], ga.pop[rng.Intn(len(ga.pop))]
+			child := individual(offspring(&ga.Parameter, a.g, b.g)[0], ga.Objectives, ga.Violation)
+			ga.pop = ga.reduce(append(ga.pop, child))
+		}
+		ga.generation++
+	}
+}
+
+// reduce returns pop without the individual of its worst front contributing
+// the least hypervolume, or violating the constraints the most if the front
+// is infeasible.
+func (ga *GASMSEMOA) reduce(pop []*gaIndividual) []*gaIndividual {
+	fronts := nonDominatedSort(pop)
+	last := fronts[len(fronts)-1]
+	worst := last[0]
+	switch {
+	case worst.violation > 0:
+		for _, x := range last {
+			if x.violation > worst.violation {
+				worst = x
+			}
+		}
+	case len(last) > 1:
+		obj := objectivesOf(last)
+		ref := ga.Reference
+		if ref == nil {
+			ref = make([]float64, len(obj[0]))
+			for k := range ref {
+				ref[k] = math.Inf(-1)
+				for _, f := range obj {
+					ref[k] = math.Max(ref[k], f[k]+1)
+				}
+			}
+		}
+		c := HypervolumeContributions(obj, ref)
+		w := 0
+		for j := range last {
+			if c[j] < c[w] {
+				w = j
+			}
+		}
+		worst = last[w]
+	}
+	next := pop[:0]
+	for _, x := range pop {
+		if x != worst {
+			next = append(next, x)
+		}
+	}
+	return next
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GASMSEMOA) Generation() int { return ga.generation }
+
+// Population returns the genomes of the population.
+func (ga *GASMSEMOA) Population() GAGenomes { return genomesOf(ga.pop) }
+
+// ParetoFront returns the genomes of the population no other genome of the
+// population dominates.
+func (ga *GASMSEMOA) ParetoFront() GAGenomes { return genomesOf(paretoFront(ga.pop)) }
+
+// ParetoObjectives returns the objective vectors of the ParetoFront, in the
+// same order.
+func (ga *GASMSEMOA) ParetoObjectives() [][]float64 { return objectivesOf(paretoFront(ga.pop)) }
@@ -0,0 +1,209 @@
+/*
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.
+
+SPEA2, the strength Pareto evolutionary algorithm of Zitzler, Laumanns and
+Thiele. The best genomes found are kept in an archive of fixed size, parents
+are picked from the archive only. Fitness counts how strong the genomes
+dominating a genome are, plus a density term that breaks ties in favour of
+genomes in sparse regions. An archive with too many non-dominated genomes is
+truncated by repeatedly dropping the genome closest to its neighbours.
+*/
+
+package ga
+
+import (
+	"math"
+	"sort"
+)
+
+type GASPEA2 struct {
+	// Initializer, Breeder, Mutator, PBreed and PMutate are used as by GA.
+	// Parents are picked from the archive by binary tournament on fitness,
+	// the Selector is not used.
+	Parameter GAParameter
+	// Objective vector of a genome, every objective is minimized. Only used
+	// for genomes that do not implement GAObjectiveGenome.
+	Objectives func(g GAGenome) []float64
+	// Constraint violation of a genome, 0 if it is feasible, nil if there
+	// are no constraints. Only used for genomes that do not implement
+	// GAConstrainedGenome.
+	Violation func(g GAGenome) float64
+	// Size of the archive, 0 uses the population size
+	ArchiveSize int
+
+	pop        []*gaIndividual
+	archive    []*gaIndividual
+	popsize    int
+	generation int
+}
+
+func NewGASPEA2(parameter GAParameter, objectives func(g GAGenome) []float64) *GASPEA2 {
+	return &GASPEA2{Parameter: parameter, Objectives: objectives}
+}
+
+func (ga *GASPEA2) String() string { return "GASPEA2" }
+
+func (ga *GASPEA2) Init(popsize int, i GAGenome) {
+	ga.popsize = popsize
+	ga.generation = 0
+	ga.pop = individuals(ga.Parameter.Initializer.InitPop(i, popsize), ga.Objectives, ga.Violation)
+	ga.archive = ga.environmental(ga.pop)
+}
+
+func (ga *GASPEA2) Optimize(gen int) {
+	for i := 0; i < gen; i++ {
+		var q GAGenomes
+		for len(q) < ga.popsize {
+			q = append(q, offspring(&ga.Parameter, ga.tournament().g, ga.tournament().g)...)
+		}
+		ga.pop = individuals(q[:ga.popsize], ga.Objectives, ga.Violation)
+		ga.archive = ga.environmental(append(append([]*gaIndividual(nil), ga.pop...), ga.archive...))
+		ga.generation++
+	}
+}
+
+// tournament returns the archive individual of lower fitness of two random
+// ones.
+func (ga *GASPEA2) tournament() *gaIndividual {
+	a, b := ga.archive[rng.Intn(len(ga.archive))], ga.archive[rng.Intn(len(ga.archive))]
+	if b.fitness < a.fitness {
+		return b
+	}
+	return a
+}
+
+func (ga *GASPEA2) archiveSize() int {
+	if ga.ArchiveSize > 0 {
+		return ga.ArchiveSize
+	}
+	return ga.popsize
+}
+
+// fitness sets the fitness of every individual of r and returns the
+// distances between them. Raw fitness is the sum of the strengths, the
+// number of individuals dominated, of the individuals dominating it, 0 for
+// non-dominated ones. Density, below 1, is 1/(d+2) with d the distance to
+// the k-th nearest individual, k the square root of the size of r.
+func fitness(r []*gaIndividual) [][]float64 {
+	n := len(r)
+	strength := make([]int, n)
+	dominates := make([][]bool, n)
+	for i := range r {
+		dominates[i] = make([]bool, n)
+		for j := range r {
+			if i != j && constrainedDominates(r[i], r[j]) {
+				dominates[i][j] = true
+				strength[i]++
+			}
+		}
+	}
+	dist := make([][]float64, n)
+	for i := range r {
+		dist[i] = make([]float64, n)
+		for j := range r {
+			dist[i][j] = distance(r[i].obj, r[j].obj)
+		}
+	}
+	k := int(math.Sqrt(float64(n)))
+	if k >= n {
+		k = n - 1
+	}
+	sorted := make([]float64, n)
+	for i := range r {
+		raw := 0
+		for j := range r {
+			if dominates[j][i] {
+				raw += strength[j]
+			}
+		}
+		copy(sorted, dist[i])
+		sort.Float64s(sorted)
+		// sorted[0] is the distance of i to itself.
+		r[i].fitness = float64(raw) + 1/(sorted[k]+2)
+	}
+	return dist
+}
+
+// environmental returns the next archive from r: the non-dominated
+// individuals, filled up with the dominated ones of lowest fitness if there
+// are too few and truncated if there are too many.
+func (ga *GASPEA2) environmental(r []*gaIndividual) []*gaIndividual {
+	dist := fitness(r)
+	size := ga.archiveSize()
+	var next []int
+	for i, x := range r {
+		if x.fitness < 1 {
+			next = append(next, i)
+		}
+	}
+	if len(next) < size {
+		idx := make([]int, len(r))
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return r[idx[a]].fitness < r[idx[b]].fitness })
+		if len(idx) > size {
+			idx = idx[:size]
+		}
+		next = idx
+	}
+	for len(next) > size {
+		c := crowded(next, dist)
+		next = append(next[:c], next[c+1:]...)
+	}
+	archive := make([]*gaIndividual, len(next))
+	for a, i := range next {
+		archive[a] = r[i]
+	}
+	return archive
+}
+
+// crowded returns the position in next of the individual to drop from an
+// archive that is too large: the one nearest to its nearest neighbour, ties
+// broken by the second nearest and so on.
+func crowded(next []int, dist [][]float64) int {
+	var worst int
+	var worstD []float64
+	d := make([]float64, 0, len(next)-1)
+	for a, i := range next {
+		d = d[:0]
+		for _, j := range next {
+			if i != j {
+				d = append(d, dist[i][j])
+			}
+		}
+		sort.Float64s(d)
+		if a == 0 || lexicographicLess(d, worstD) {
+			worst, worstD = a, append(worstD[:0], d...)
+		}
+	}
+	return worst
+}
+
+func lexicographicLess(a, b []float64) bool {
+	for k := range a {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return false
+}
+
+// Generation returns the number of generations optimized since Init.
+func (ga *GASPEA2) Generation() int { return ga.generation }
+
+// Population returns the genomes of the population, the children of the last
+// generation.
+func (ga *GASPEA2) Population() GAGenomes { return genomesOf(ga.pop) }
+
+// Archive returns the genomes of the archive.
+func (ga *GASPEA2) Archive() GAGenomes { return genomesOf(ga.archive) }
+
+// ParetoFront returns the genomes of the archive no other genome of the
+// archive dominates.
+func (ga *GASPEA2) ParetoFront() GAGenomes { return genomesOf(paretoFront(ga.archive)) }
+
+// ParetoObjectives returns the objective vectors of the ParetoFront, in the
+// same order.
+func (ga *GASPEA2) ParetoObjectives() [][]float64 { return objectivesOf(paretoFront(ga.archive)) }
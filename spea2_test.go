@@ -0,0 +1,51 @@
+package ga
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSPEA2Truncation(t *testing.T) {
+	// Five points on a line, the middle two closest to their neighbours.
+	var r []*gaIndividual
+	for _, x := range []float64{0, 0.3, 0.4, 0.5, 1} {
+		r = append(r, &gaIndividual{obj: []float64{x, 1 - x}})
+	}
+	ga := &GASPEA2{ArchiveSize: 3}
+	archive := ga.environmental(r)
+	var got []float64
+	for _, x := range archive {
+		got = append(got, x.obj[0])
+	}
+	if len(got) != 3 || got[0] != 0 || got[2] != 1 || math.Abs(got[1]-0.4) > 0.15 {
+		t.Errorf("archive %v; want 0, one of the middle points and 1", got)
+	}
+}
+
+func TestSPEA2(t *testing.T) {
+	ga := NewGASPEA2(moeaParameter(), zdt1)
+	ga.Init(60, NewFloatGenome(make([]float64, 8), nil, 1, 0))
+	ga.Optimize(200)
+	if ga.Generation() != 200 || len(ga.Population()) != 60 || len(ga.Archive()) != 60 {
+		t.Errorf("generation %d with %d genomes and %d in the archive; want 200, 60 and 60",
+			ga.Generation(), len(ga.Population()), len(ga.Archive()))
+	}
+	checkZDT1(t, "SPEA2", ga.ParetoObjectives())
+}
+
+func TestSMSEMOA(t *testing.T) {
+	ga := NewGASMSEMOA(moeaParameter(), zdt1)
+	ga.Init(40, NewFloatGenome(make([]float64, 8), nil, 1, 0))
+	ref := []float64{1.1, 1.1}
+	ga.Optimize(20)
+	before := Hypervolume(ga.ParetoObjectives(), ref)
+	ga.Optimize(130)
+	if len(ga.Population()) != 40 {
+		t.Errorf("%d genomes; want 40", len(ga.Population()))
+	}
+	front := ga.ParetoObjectives()
+	checkZDT1(t, "SMS-EMOA", front)
+	if hv := Hypervolume(front, ref); hv <= before {
+		t.Errorf("hypervolume %v after 150 generations; want more than %v after 20", hv, before)
+	}
+}